@@ -3,7 +3,6 @@ package example
 import (
 	"context"
 	"fmt"
-	"net/http"
 
 	"github.com/magicx-ai/groq-go/groq"
 )
@@ -13,7 +12,7 @@ const (
 )
 
 func ExampleClient_CreateChatCompletion() {
-	cli := groq.NewClient(apiKey, &http.Client{})
+	cli := groq.NewClient(apiKey)
 
 	req := groq.ChatCompletionRequest{
 		Messages: []groq.Message{
@@ -24,13 +23,13 @@ func ExampleClient_CreateChatCompletion() {
 		},
 		Model:       groq.ModelIDLLAMA370B,
 		MaxTokens:   150,
-		Temperature: 0.7,
-		TopP:        0.9,
-		NumChoices:  1,
+		Temperature: groq.Ptr(0.7),
+		TopP:        groq.Ptr(0.9),
+		NumChoices:  groq.Ptr(1),
 		Stream:      false,
 	}
 
-	resp, err := cli.CreateChatCompletion(req)
+	resp, err := cli.CreateChatCompletion(context.Background(), req)
 	if err != nil {
 		fmt.Println(fmt.Errorf("error is occurred: %v", err))
 		return
@@ -40,9 +39,9 @@ func ExampleClient_CreateChatCompletion() {
 }
 
 func ExampleClient_ListModels() {
-	client := groq.NewClient(apiKey, &http.Client{})
+	client := groq.NewClient(apiKey)
 
-	modelsResponse, err := client.ListModels()
+	modelsResponse, err := client.ListModels(context.Background())
 	if err != nil {
 		fmt.Println(fmt.Errorf("error is occurred: %v", err))
 		return
@@ -59,13 +58,13 @@ func ExampleClient_ListModels() {
 }
 
 func ExampleClient_RetrieveModel() {
-	client := groq.NewClient(apiKey, &http.Client{})
+	client := groq.NewClient(apiKey)
 
 	// You can use the predefined model IDs
 	modelID := groq.ModelIDMIXTRAL
 	// Or you can find the model ID from the ListModels API
 	// modelID = groq.ModelID("mixtral-8x7b-32768")
-	modelResponse, err := client.RetrieveModel(modelID)
+	modelResponse, err := client.RetrieveModel(context.Background(), modelID)
 	if err != nil {
 		fmt.Println(fmt.Errorf("error is occurred: %v", err))
 		return
@@ -75,7 +74,7 @@ func ExampleClient_RetrieveModel() {
 }
 
 func ExampleClient_CreateChatCompletionStream() {
-	cli := groq.NewClient(apiKey, &http.Client{})
+	cli := groq.NewClient(apiKey)
 
 	req := groq.ChatCompletionRequest{
 		Messages: []groq.Message{
@@ -86,26 +85,24 @@ func ExampleClient_CreateChatCompletionStream() {
 		},
 		Model:       groq.ModelIDLLAMA370B,
 		MaxTokens:   1000,
-		Temperature: 0.7,
-		TopP:        0.9,
-		NumChoices:  1,
+		Temperature: groq.Ptr(0.7),
+		TopP:        groq.Ptr(0.9),
+		NumChoices:  groq.Ptr(1),
 		Stream:      true,
 	}
 
-	respCh, closer, err := cli.CreateChatCompletionStream(context.Background(), req)
-	if closer != nil {
-		defer closer()
-	}
+	stream, err := cli.CreateChatCompletionStream(context.Background(), req)
 	if err != nil {
 		fmt.Println(fmt.Errorf("error is occurred: %v", err))
 		return
 	}
+	defer stream.Close()
 
-	for res := range respCh {
-		if res.Error != nil {
-			fmt.Println(fmt.Errorf("error is occurred: %v", res.Error))
+	for chunk, err := range stream.All() {
+		if err != nil {
+			fmt.Println(fmt.Errorf("error is occurred: %v", err))
 			break
 		}
-		fmt.Printf("Response: %+v\n", res.Response.Choices[0].Delta)
+		fmt.Printf("Response: %+v\n", chunk.Choices[0].Delta)
 	}
 }
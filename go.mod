@@ -1,11 +1,8 @@
 module github.com/magicx-ai/groq-go
 
-go 1.22.3
+go 1.23
 
-require (
-	github.com/pkg/errors v0.9.1
-	github.com/tmaxmax/go-sse v0.8.0
-)
+require github.com/pkg/errors v0.9.1
 
 require github.com/fortytw2/leaktest v1.3.0
 
@@ -13,5 +10,5 @@ require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/testify v1.9.0
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,135 @@
+package groq
+
+import (
+	"slices"
+	"sort"
+)
+
+// StreamAccumulator rebuilds the full response of a streamed chat completion
+// from its chunks: the content of each choice is joined, the fragments of
+// its tool calls are put together, and its finish reason and the usage of the
+// request are kept. The zero value is ready to use.
+//
+//	var acc groq.StreamAccumulator
+//	for chunk, err := range stream.All() {
+//		if err != nil {
+//			return err
+//		}
+//		acc.Add(chunk)
+//	}
+//	completion := acc.Response()
+type StreamAccumulator struct {
+	resp    ChatCompletionResponse
+	choices map[int]*accumulatedChoice
+}
+
+type accumulatedChoice struct {
+	Choice
+	toolCalls map[int]*ToolCall
+}
+
+// Add folds a chunk of the stream into the accumulator.
+func (a *StreamAccumulator) Add(chunk *ChatCompletionChunk) {
+	if a.resp.ID == "" {
+		a.resp.ID = chunk.ID
+		a.resp.Object = "chat.completion"
+		a.resp.Created = chunk.Created
+		a.resp.Model = chunk.Model
+	}
+	if chunk.SystemFingerprint != "" {
+		a.resp.SystemFingerprint = chunk.SystemFingerprint
+	}
+	if chunk.XGroq != nil {
+		a.resp.XGroq = chunk.XGroq
+	}
+	if chunk.RateLimit != nil {
+		a.resp.RateLimit = chunk.RateLimit
+	}
+	if chunk.Meta != nil {
+		a.resp.Meta = chunk.Meta
+	}
+	if usage := chunk.streamUsage(); usage != nil {
+		a.resp.Usage = *usage
+	}
+
+	for _, c := range chunk.Choices {
+		if a.choices == nil {
+			a.choices = make(map[int]*accumulatedChoice)
+		}
+		choice, ok := a.choices[c.Index]
+		if !ok {
+			choice = &accumulatedChoice{Choice: Choice{Index: c.Index}, toolCalls: make(map[int]*ToolCall)}
+			a.choices[c.Index] = choice
+		}
+
+		if c.Delta.Role != "" {
+			choice.Message.Role = c.Delta.Role
+		}
+		if c.Delta.Name != "" {
+			choice.Message.Name = c.Delta.Name
+		}
+		choice.Message.Content += c.Delta.Content
+
+		for _, fragment := range c.Delta.ToolCalls {
+			call, ok := choice.toolCalls[fragment.Index]
+			if !ok {
+				call = &ToolCall{Index: fragment.Index}
+				choice.toolCalls[fragment.Index] = call
+			}
+
+			if fragment.ID != "" {
+				call.ID = fragment.ID
+			}
+			if fragment.Type != "" {
+				call.Type = fragment.Type
+			}
+			if fragment.Function.Name != "" {
+				call.Function.Name = fragment.Function.Name
+			}
+			call.Function.Arguments += fragment.Function.Arguments
+		}
+
+		if c.Logprobs != nil {
+			if choice.Logprobs == nil {
+				choice.Logprobs = &Logprobs{}
+			}
+			choice.Logprobs.Content = append(choice.Logprobs.Content, c.Logprobs.Content...)
+		}
+		if c.FinishReason != "" {
+			choice.FinishReason = c.FinishReason
+		}
+	}
+}
+
+// Response returns the response accumulated so far, with its choices and
+// their tool calls in index order.
+func (a *StreamAccumulator) Response() *ChatCompletionResponse {
+	resp := a.resp
+	resp.Choices = make([]Choice, 0, len(a.choices))
+	for _, index := range sortedKeys(a.choices) {
+		choice := a.choices[index]
+
+		c := choice.Choice
+		if c.Logprobs != nil {
+			c.Logprobs = &Logprobs{Content: slices.Clone(c.Logprobs.Content)}
+		}
+		c.Message.ToolCalls = nil
+		for _, i := range sortedKeys(choice.toolCalls) {
+			c.Message.ToolCalls = append(c.Message.ToolCalls, *choice.toolCalls[i])
+		}
+		resp.Choices = append(resp.Choices, c)
+	}
+
+	return &resp
+}
+
+// sortedKeys returns the keys of m in increasing order.
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	return keys
+}
@@ -0,0 +1,50 @@
+package groq
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamAccumulator(t *testing.T) {
+	chunks := []string{
+		`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"llama3-70b-8192","choices":[{"index":0,"delta":{"role":"assistant","content":"Let me "}},{"index":1,"delta":{"role":"assistant","content":"Sunny"}}],"x_groq":{"id":"req_123"}}`,
+		`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"check.","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"loc"}}]}}]}`,
+		`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_2","type":"function","function":{"name":"get_time","arguments":"{}"}},{"index":0,"function":{"arguments":"ation\":\"Seoul\"}"}}]}}]}`,
+		`{"id":"chatcmpl-1","choices":[{"index":1,"delta":{},"finish_reason":"stop"},{"index":0,"delta":{},"finish_reason":"tool_calls"}],"x_groq":{"id":"req_123","usage":{"prompt_tokens":10,"completion_tokens":20,"total_tokens":30}}}`,
+	}
+
+	var acc StreamAccumulator
+	for _, data := range chunks {
+		var chunk ChatCompletionResponse
+		require.NoError(t, json.Unmarshal([]byte(data), &chunk))
+		acc.Add(&chunk)
+	}
+
+	resp := acc.Response()
+	assert.Equal(t, "chatcmpl-1", resp.ID)
+	assert.Equal(t, "chat.completion", resp.Object)
+	assert.Equal(t, "llama3-70b-8192", resp.Model)
+	assert.Equal(t, Usage{PromptTokens: 10, CompletionTokens: 20, TotalTokens: 30}, resp.Usage)
+
+	require.Len(t, resp.Choices, 2)
+	assert.Equal(t, Choice{
+		Index: 0,
+		Message: Message{
+			Role:    MessageRoleAssistant,
+			Content: "Let me check.",
+			ToolCalls: []ToolCall{
+				{ID: "call_1", Type: ToolTypeFunction, Function: ToolCallFunction{Name: "get_weather", Arguments: `{"location":"Seoul"}`}},
+				{Index: 1, ID: "call_2", Type: ToolTypeFunction, Function: ToolCallFunction{Name: "get_time", Arguments: "{}"}},
+			},
+		},
+		FinishReason: "tool_calls",
+	}, resp.Choices[0])
+	assert.Equal(t, Choice{
+		Index:        1,
+		Message:      Message{Role: MessageRoleAssistant, Content: "Sunny"},
+		FinishReason: "stop",
+	}, resp.Choices[1])
+}
@@ -0,0 +1,261 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultBaseURL   = "https://api.groq.com/openai"
+	defaultUserAgent = "groq-go"
+)
+
+// Client is the Groq API client. Every method takes a context.Context that
+// bounds the request. This breaks callers of the context-free methods of
+// earlier releases, which LegacyClient keeps.
+type Client interface {
+	CreateChatCompletion(context.Context, ChatCompletionRequest) (*ChatCompletionResponse, error)
+	CreateChatCompletionStream(context.Context, ChatCompletionRequest) (*ChatCompletionStream, error)
+	ListModels(context.Context) (*ListModelsResponse, error)
+	RetrieveModel(context.Context, ModelID) (*Model, error)
+}
+
+var _ Client = (*client)(nil)
+
+type client struct {
+	keys KeyProvider
+	// baseURL shouldn't end with a trailing slash
+	baseURL      string
+	client       *http.Client
+	userAgent    string
+	headers      http.Header
+	defaultModel ModelID
+	// timeout bounds every non-streaming request; zero means no limit.
+	timeout time.Duration
+	// firstTokenTimeout and idleTimeout bound the waits for the chunks of
+	// streams; zero means no limit.
+	firstTokenTimeout time.Duration
+	idleTimeout       time.Duration
+	retryPolicy       RetryPolicy
+	limiter           *Limiter
+	// rawResponseBody keeps response bodies in ResponseMeta.
+	rawResponseBody bool
+
+	middleware       []Middleware
+	streamMiddleware []StreamMiddleware
+	// chatHandler and streamHandler are the middleware chains ending in
+	// createChatCompletion and createChatCompletionStream.
+	chatHandler   Handler
+	streamHandler StreamHandler
+}
+
+// NewClient creates a Client authenticated with apiKey. Without options it
+// talks to the public Groq API using a fresh http.Client.
+func NewClient(apiKey string, opts ...Option) Client {
+	c := &client{
+		keys:      staticKey(apiKey),
+		baseURL:   defaultBaseURL,
+		client:    &http.Client{},
+		userAgent: defaultUserAgent,
+		headers:   http.Header{},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.chatHandler = chain(c.createChatCompletion, c.middleware)
+	c.streamHandler = chain(c.createChatCompletionStream, c.streamMiddleware)
+
+	return c
+}
+
+// newRequest builds an API request for path, applying the client's headers.
+// The Authorization header is set for each attempt by authorize.
+// If body is not nil, it is marshalled as the JSON request body.
+func (c *client) newRequest(ctx context.Context, method, path string, body any) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(jsonData)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	for key, values := range c.headers {
+		for _, value := range values {
+			httpReq.Header.Add(key, value)
+		}
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.userAgent != "" {
+		httpReq.Header.Set("User-Agent", c.userAgent)
+	}
+
+	return httpReq, nil
+}
+
+// do sends httpReq and decodes the JSON body of a successful response into out.
+// Responses with a non-2xx status code are returned as *APIError.
+func (c *client) do(httpReq *http.Request, out any) (*ResponseMeta, error) {
+	resp, attempt, err := c.send(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	meta := attempt.meta(resp)
+	if c.rawResponseBody {
+		meta.Body = body
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal response")
+	}
+
+	return meta, nil
+}
+
+// send sends httpReq, retrying transient failures according to the client's
+// retry policy. On success the caller must close the response body, and the
+// returned requestAttempt describes the successful attempt. Responses with a
+// non-2xx status code are returned as *APIError.
+func (c *client) send(httpReq *http.Request) (*http.Response, *requestAttempt, error) {
+	ctx := httpReq.Context()
+
+	for attempt := 1; ; attempt++ {
+		req := httpReq
+		if attempt > 1 {
+			var err error
+			if req, err = rewindRequest(httpReq); err != nil {
+				return nil, nil, errors.Wrap(err, "failed to rewind request")
+			}
+		}
+
+		key, err := c.authorize(req)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		req, tracked := trackAttempt(req, key)
+		resp, err := c.client.Do(req)
+		if err != nil {
+			c.keys.Report(key, 0, nil)
+			if !c.retryPolicy.canRetry(attempt) || !isRetryableError(err, req.Method, tracked.wrote.Load()) {
+				return nil, nil, errors.Wrap(err, "failed to send request")
+			}
+			if err := sleep(ctx, c.retryPolicy.backoff(attempt)); err != nil {
+				return nil, nil, errors.Wrap(err, "failed to send request")
+			}
+
+			continue
+		}
+
+		c.keys.Report(key, resp.StatusCode, resp.Header)
+		if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+			return resp, tracked, nil
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to read response body")
+		}
+
+		apiErr := newAPIError(resp, body, c.keyID(key))
+		if !c.retryPolicy.canRetry(attempt) || !c.retryPolicy.retryableStatus(resp.StatusCode) {
+			return nil, nil, apiErr
+		}
+		if err := sleep(ctx, c.retryDelay(attempt, resp)); err != nil {
+			return nil, nil, apiErr
+		}
+	}
+}
+
+// retryDelay returns the wait before retrying the attempt that got resp.
+// A rate limit applies to the key it was reported for, so when the client
+// rotates keys, the retry doesn't wait for it to reset: it uses the normal
+// backoff, and a KeyPool hands out another key or waits for one itself.
+func (c *client) retryDelay(attempt int, resp *http.Response) time.Duration {
+	if _, static := c.keys.(staticKey); !static && resp.StatusCode == http.StatusTooManyRequests {
+		return c.retryPolicy.backoff(attempt)
+	}
+
+	return c.retryPolicy.delay(attempt, resp.Header)
+}
+
+// keyID returns the redacted form of key for errors, or "" if the client
+// has a single key.
+func (c *client) keyID(key string) string {
+	if _, static := c.keys.(staticKey); static {
+		return ""
+	}
+
+	return redactKey(key)
+}
+
+// authorize sets the Authorization header of httpReq to the next key of the
+// client's KeyProvider, and returns that key.
+func (c *client) authorize(httpReq *http.Request) (string, error) {
+	key, err := c.keys.Key(httpReq.Context())
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get API key")
+	}
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", key))
+
+	return key, nil
+}
+
+// withTimeout derives a context bounded by the client's request timeout.
+func (c *client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// reserve waits until the client's limiter admits req. It returns a nil
+// Reservation, which is safe to reconcile, if the client has no limiter.
+func (c *client) reserve(ctx context.Context, req ChatCompletionRequest) (*Reservation, error) {
+	if c.limiter == nil {
+		return nil, nil
+	}
+
+	reservation, err := c.limiter.Wait(ctx, req.Model, EstimateTokens(req))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to wait for rate limiter")
+	}
+
+	return reservation, nil
+}
+
+// withDefaults fills in request fields the caller left empty.
+func (c *client) withDefaults(req ChatCompletionRequest) ChatCompletionRequest {
+	if req.Model == "" {
+		req.Model = c.defaultModel
+	}
+
+	return req
+}
@@ -0,0 +1,66 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/openai/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		assert.Equal(t, "test-agent", r.Header.Get("User-Agent"))
+		assert.Equal(t, "search", r.Header.Get("X-Team"))
+
+		var req ChatCompletionRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, ModelIDLLAMA38B, req.Model)
+
+		_ = json.NewEncoder(w).Encode(ChatCompletionResponse{Model: string(req.Model)})
+	}))
+	defer server.Close()
+
+	c := NewClient("test-key",
+		WithBaseURL(server.URL+"/openai/"),
+		WithHTTPClient(server.Client()),
+		WithUserAgent("test-agent"),
+		WithDefaultHeaders(http.Header{"X-Team": []string{"search"}}),
+		WithDefaultModel(ModelIDLLAMA38B),
+	)
+
+	resp, err := c.CreateChatCompletion(context.Background(), ChatCompletionRequest{
+		Messages: []Message{{Role: MessageRoleUser, Content: "hi"}},
+	})
+	require.NoError(t, err)
+	assert.EqualValues(t, ModelIDLLAMA38B, resp.Model)
+}
+
+func TestResponseMeta(t *testing.T) {
+	const body = `{"id":"chatcmpl-1","choices":[]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("x-request-id", "req_123")
+		w.Header().Set("x-ratelimit-remaining-tokens", "17997")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	c := NewClient("test-key", WithBaseURL(server.URL), WithRawResponseBody())
+
+	resp, err := c.CreateChatCompletion(context.Background(), ChatCompletionRequest{
+		Messages: []Message{{Role: MessageRoleUser, Content: "hi"}},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Meta)
+	assert.Equal(t, http.StatusOK, resp.Meta.StatusCode)
+	assert.Equal(t, "req_123", resp.Meta.RequestID)
+	assert.Equal(t, body, string(resp.Meta.Body))
+	assert.Positive(t, resp.Meta.Latency)
+	assert.LessOrEqual(t, resp.Meta.TimeToFirstByte, resp.Meta.Latency)
+	assert.Equal(t, 17997, resp.RateLimit.RemainingTokens)
+}
@@ -1,67 +1,111 @@
 package groq
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
-
-	"github.com/pkg/errors"
-)
-
-const (
-	baseURL = "https://api.groq.com/openai"
 )
 
-type Client interface {
-	CreateChatCompletion(ChatCompletionRequest) (*ChatCompletionResponse, error)
-	CreateChatCompletionStream(context.Context, ChatCompletionRequest) (<-chan *ChatCompletionStreamResponse, func(), error)
-	ListModels() (*ListModelsResponse, error)
-	RetrieveModel(ModelID) (*Model, error)
+// ChatCompletionRequest represents the request body for creating a chat completion.
+// Optional parameters are pointers, so that zero values such as a temperature
+// of 0 are sent while nil ones are left out; use Ptr to set them inline.
+type ChatCompletionRequest struct {
+	Messages         []Message           `json:"messages"`                    // A list of messages comprising the conversation so far.
+	Model            ModelID             `json:"model"`                       // ID of the model to use
+	MaxTokens        int                 `json:"max_tokens,omitempty"`        // The maximum number of tokens that can be generated in the chat completion. The total length of input tokens and generated tokens is limited by the model's context length.
+	Temperature      *float64            `json:"temperature,omitempty"`       // Sampling temperature, between 0 and 2; nil uses the API default
+	TopP             *float64            `json:"top_p,omitempty"`             // Nucleus sampling probability; nil uses the API default
+	NumChoices       *int                `json:"n,omitempty"`                 // Number of completion choices to generate; nil uses the API default
+	PresencePenalty  *float64            `json:"presence_penalty,omitempty"`  // Penalty for presence of tokens, between -2.0 and 2.0; nil uses the API default
+	FrequencyPenalty *float64            `json:"frequency_penalty,omitempty"` // Number between -2.0 and 2.0. Positive values penalize new tokens based on their existing frequency in the text so far, decreasing the model's likelihood to repeat the same line verbatim.
+	UserID           string              `json:"user,omitempty"`              // Unique identifier for the end-user
+	Stream           bool                `json:"stream,omitempty"`            // If set, partial message deltas will be sent as data-only server-sent events
+	StreamOptions    *StreamOptions      `json:"stream_options,omitempty"`    // Options of a streamed completion; requires Stream
+	ToolChoice       *ToolChoice         `json:"tool_choice,omitempty"`       // Controls which tool is called by the model
+	Tools            []Tool              `json:"tools,omitempty"`             // List of tools the model may call
+	FunctionCall     *FunctionCallChoice `json:"function_call,omitempty"`     // Controls which function is called by the model; superseded by ToolChoice
+	ResponseFormat   ResponseFormat      `json:"response_format,omitempty"`   // Format of the model's response
+	Seed             *int                `json:"seed,omitempty"`              // Seed for deterministic sampling; nil samples randomly
+	Logprobs         bool                `json:"logprobs,omitempty"`          // Whether to return the log probabilities of the generated tokens
+	TopLogprobs      *int                `json:"top_logprobs,omitempty"`      // Number of most likely tokens to return at each position, between 0 and 20; requires Logprobs
+	StopSequences    Stop                `json:"stop,omitempty"`              // Sequences where the model stops generating, e.g. punctuation marks and markers like "[end]"
+
+	// ExtraFields are sent as additional fields of the request body, for
+	// parameters this package doesn't support yet, e.g.
+	// {"service_tier": "flex"}. They take precedence over the fields above.
+	// Unknown fields of a decoded request are kept here.
+	ExtraFields map[string]any `json:"-"`
 }
 
-var _ Client = (*client)(nil)
+// Ptr returns a pointer to v, for setting the optional parameters of a
+// request, e.g. Temperature: groq.Ptr(0.0).
+func Ptr[T any](v T) *T {
+	return &v
+}
 
-type client struct {
-	apiKey string
-	// baseURL shouldn't end with a trailing slash
-	baseURL string
-	client  *http.Client
+func (r ChatCompletionRequest) MarshalJSON() ([]byte, error) {
+	type request ChatCompletionRequest
+	return marshalWithExtra(request(r), r.ExtraFields)
 }
 
-// ChatCompletionRequest represents the request body for creating a chat completion.
-type ChatCompletionRequest struct {
-	Messages         []Message   `json:"messages"`                    // A list of messages comprising the conversation so far.
-	Model            ModelID     `json:"model"`                       // ID of the model to use
-	MaxTokens        int         `json:"max_tokens,omitempty"`        // The maximum number of tokens that can be generated in the chat completion. The total length of input tokens and generated tokens is limited by the model's context length.
-	Temperature      float64     `json:"temperature,omitempty"`       // Sampling temperature
-	TopP             float64     `json:"top_p,omitempty"`             // Nucleus sampling probability
-	NumChoices       int         `json:"n,omitempty"`                 // Number of completion choices to generate
-	PresencePenalty  float64     `json:"presence_penalty,omitempty"`  // Penalty for presence of tokens
-	FrequencyPenalty *float64    `json:"frequency_penalty,omitempty"` // Number between -2.0 and 2.0. Positive values penalize new tokens based on their existing frequency in the text so far, decreasing the model's likelihood to repeat the same line verbatim.
-	UserID           string      `json:"user,omitempty"`              // Unique identifier for the end-user
-	Stream           bool        `json:"stream,omitempty"`            // If set, partial message deltas will be sent as data-only server-sent events
-	ToolChoice       interface{} `json:"tool_choice,omitempty"`       // Controls which tool is called by the model
-	Tools            interface{} `json:"tools,omitempty"`             // List of tools the model may call
-	FunctionCall     interface{} `json:"function_call,omitempty"`     // Controls which function is called by the model
-	ResponseFormat   interface{} `json:"response_format,omitempty"`   // Format of the model's response
-	Seed             int         `json:"seed,omitempty"`              // Seed for deterministic sampling
-
-	// StopSequences is a predefined or user-specified text string that
-	// signals an AI to stop generating content, ensuring its responses
-	// remain focused and concise. Examples include punctuation marks and
-	// markers like "[end]".
-	StopSequences interface{} `json:"stop,omitempty"`
+func (r *ChatCompletionRequest) UnmarshalJSON(data []byte) error {
+	type request ChatCompletionRequest
+	aux := struct {
+		*request
+		ResponseFormat json.RawMessage `json:"response_format,omitempty"`
+	}{request: (*request)(r)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	extra, err := unknownFields(data, ChatCompletionRequest{})
+	if err != nil {
+		return err
+	}
+	r.ExtraFields = extra
+
+	r.ResponseFormat = nil
+	if len(aux.ResponseFormat) > 0 && string(aux.ResponseFormat) != "null" {
+		format, err := unmarshalResponseFormat(aux.ResponseFormat)
+		if err != nil {
+			return err
+		}
+		r.ResponseFormat = format
+	}
+
+	return nil
 }
 
 // Choice represents a single completion choice returned by the chat completion API.
 type Choice struct {
-	Index        int     `json:"index"`         // Index of the choice
-	Message      Message `json:"message"`       // Message generated by the model
-	Delta        Message `json:"delta"`         // Partial generated message when you are streaming
-	FinishReason string  `json:"finish_reason"` // Reason why the model stopped generating tokens
+	Index        int       `json:"index"`              // Index of the choice
+	Message      Message   `json:"message"`            // Message generated by the model
+	Delta        Message   `json:"delta"`              // Partial generated message when you are streaming
+	Logprobs     *Logprobs `json:"logprobs,omitempty"` // Log probabilities of the generated tokens, if requested; for the tokens of the chunk when you are streaming
+	FinishReason string    `json:"finish_reason"`      // Reason why the model stopped generating tokens
+
+	Extra map[string]any `json:"-"` // Fields of the choice this package doesn't know about
+}
+
+func (c Choice) MarshalJSON() ([]byte, error) {
+	type choice Choice
+	return marshalWithExtra(choice(c), c.Extra)
+}
+
+func (c *Choice) UnmarshalJSON(data []byte) error {
+	type choice Choice
+	if err := json.Unmarshal(data, (*choice)(c)); err != nil {
+		return err
+	}
+
+	extra, err := unknownFields(data, Choice{})
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+
+	return nil
 }
 
 // ChatCompletionResponse represents the response from the chat completion API.
@@ -73,6 +117,43 @@ type ChatCompletionResponse struct {
 	SystemFingerprint string   `json:"system_fingerprint"` // System fingerprint
 	Choices           []Choice `json:"choices"`            // List of completion choices
 	Usage             Usage    `json:"usage"`              // Token usage information
+	XGroq             *XGroq   `json:"x_groq,omitempty"`   // Groq metadata, sent with streamed chunks
+
+	RateLimit *RateLimitInfo `json:"-"` // Rate limit state reported with the response
+	Meta      *ResponseMeta  `json:"-"` // HTTP response the completion was decoded from
+
+	Extra map[string]any `json:"-"` // Fields of the response this package doesn't know about
+}
+
+func (r ChatCompletionResponse) MarshalJSON() ([]byte, error) {
+	type response ChatCompletionResponse
+	return marshalWithExtra(response(r), r.Extra)
+}
+
+func (r *ChatCompletionResponse) UnmarshalJSON(data []byte) error {
+	type response ChatCompletionResponse
+	if err := json.Unmarshal(data, (*response)(r)); err != nil {
+		return err
+	}
+
+	extra, err := unknownFields(data, ChatCompletionResponse{})
+	if err != nil {
+		return err
+	}
+	r.Extra = extra
+
+	return nil
+}
+
+// StreamOptions are the options of a streamed chat completion.
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage,omitempty"` // If set, a last chunk with no choices carries the usage of the whole request
+}
+
+// XGroq is the Groq metadata of a streamed chunk.
+type XGroq struct {
+	ID    string `json:"id"`              // ID of the request
+	Usage *Usage `json:"usage,omitempty"` // Token usage and timing of the whole request, sent with the last chunk
 }
 
 // Usage represents the token usage information in the chat completion response.
@@ -83,59 +164,58 @@ type Usage struct {
 	PromptTime       float64 `json:"prompt_time"`       // Time taken for the prompt
 	CompletionTime   float64 `json:"completion_time"`   // Time taken for the completion
 	TotalTime        float64 `json:"total_time"`        // Total time taken
+	QueueTime        float64 `json:"queue_time"`        // Time the request spent queued
 }
 
-func NewClient(apiKey string, httpClient *http.Client) Client {
-	return &client{
-		apiKey: apiKey,
-		client: httpClient,
-		// NOTE(@Kcrong): Need to handle if the user wants to use a different base URL
-		baseURL: baseURL,
+// streamUsage returns the usage of the whole request if the chunk carries it,
+// in its x_groq metadata or, with StreamOptions.IncludeUsage, in its usage.
+func (r *ChatCompletionResponse) streamUsage() *Usage {
+	if r.XGroq != nil && r.XGroq.Usage != nil {
+		return r.XGroq.Usage
+	}
+	if r.Usage.TotalTokens > 0 {
+		return &r.Usage
 	}
+
+	return nil
 }
 
 // CreateChatCompletion sends a request to create a chat completion.
-func (c *client) CreateChatCompletion(req ChatCompletionRequest) (*ChatCompletionResponse, error) {
-	if req.Stream {
-		return nil, fmt.Errorf("use CreateChatCompletionStream for streaming completions")
-	}
+func (c *client) CreateChatCompletion(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error) {
+	req = c.withDefaults(req)
 
-	url := fmt.Sprintf("%s/v1/chat/completions", c.baseURL)
+	return c.chatHandler(ctx, req)
+}
 
-	jsonData, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %v", err)
+// createChatCompletion is the Handler that sends the request to the API.
+func (c *client) createChatCompletion(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error) {
+	if err := req.validate(false); err != nil {
+		return nil, err
 	}
 
-	httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonData))
+	reservation, err := c.reserve(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		return nil, err
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 
-	resp, err := c.client.Do(httpReq)
+	httpReq, err := c.newRequest(ctx, http.MethodPost, "/v1/chat/completions", req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
-	}
-	defer func(Body io.ReadCloser) {
-		_ = Body.Close()
-	}(resp.Body)
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to read response body")
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("invalid status code: %d, body: %s", resp.StatusCode, body)
+		reservation.Reconcile(0)
+		return nil, err
 	}
 
 	var chatResp ChatCompletionResponse
-	if err := json.Unmarshal(body, &chatResp); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal response")
+	meta, err := c.do(httpReq, &chatResp)
+	if err != nil {
+		reservation.Reconcile(0)
+		return nil, err
 	}
+	reservation.Reconcile(chatResp.Usage.TotalTokens)
+	chatResp.RateLimit = ParseRateLimitInfo(meta.Header)
+	chatResp.Meta = meta
 
 	return &chatResp, nil
 }
@@ -27,9 +27,9 @@ func TestCreateChatCompletion(t *testing.T) {
 		Timeout: 3 * time.Second,
 	}
 
-	c := NewClient(apiKey, httpCli)
+	c := NewClient(apiKey, WithHTTPClient(httpCli))
 
-	completion, err := c.CreateChatCompletion(ChatCompletionRequest{
+	completion, err := c.CreateChatCompletion(context.Background(), ChatCompletionRequest{
 		Messages: []Message{
 			{
 				Role:    MessageRoleSystem,
@@ -42,7 +42,7 @@ func TestCreateChatCompletion(t *testing.T) {
 		},
 		Model:      ModelIDLLAMA370B,
 		MaxTokens:  1,
-		NumChoices: 1,
+		NumChoices: Ptr(1),
 	})
 	require.NoError(t, err, "failed to create chat completion")
 
@@ -59,13 +59,9 @@ func TestCreateChatCompletionStream(t *testing.T) {
 
 	defer leaktest.Check(t)()
 
-	httpCli := &http.Client{
-		Timeout: 3 * time.Second,
-	}
-
-	c := NewClient(apiKey, httpCli)
+	c := NewClient(apiKey, WithFirstTokenTimeout(3*time.Second), WithIdleTimeout(time.Second))
 	ctx := context.Background()
-	stream, closer, err := c.CreateChatCompletionStream(ctx, ChatCompletionRequest{
+	stream, err := c.CreateChatCompletionStream(ctx, ChatCompletionRequest{
 		Messages: []Message{
 			{
 				Role:    MessageRoleSystem,
@@ -78,20 +74,18 @@ func TestCreateChatCompletionStream(t *testing.T) {
 		},
 		Model:      ModelIDLLAMA370B,
 		MaxTokens:  1,
-		NumChoices: 1,
+		NumChoices: Ptr(1),
 		Stream:     true,
 	})
-	if closer != nil {
-		defer closer()
-	}
 	require.NoError(t, err, "failed to create chat completion")
+	defer stream.Close()
 
-	for r := range stream {
-		require.NoError(t, r.Error, "failed to get response")
-		assert.EqualValues(t, ModelIDLLAMA370B, r.Response.Model)
-		assert.Len(t, r.Response.Choices, 1)
+	for chunk, err := range stream.All() {
+		require.NoError(t, err, "failed to get response")
+		assert.EqualValues(t, ModelIDLLAMA370B, chunk.Model)
+		assert.Len(t, chunk.Choices, 1)
 	}
-	assert.Empty(t, stream, "stream should be empty")
+	assert.False(t, stream.Next(), "stream should be closed")
 }
 
 func TestListModels(t *testing.T) {
@@ -103,8 +97,8 @@ func TestListModels(t *testing.T) {
 		Timeout: 3 * time.Second,
 	}
 
-	c := NewClient(apiKey, httpCli)
-	models, err := c.ListModels()
+	c := NewClient(apiKey, WithHTTPClient(httpCli))
+	models, err := c.ListModels(context.Background())
 	require.NoError(t, err, "failed to list models")
 	require.NotEmpty(t, models, "models list is empty")
 
@@ -155,9 +149,9 @@ func TestRetrieveModel(t *testing.T) {
 				Timeout: 3 * time.Second,
 			}
 
-			c := NewClient(apiKey, httpCli)
+			c := NewClient(apiKey, WithHTTPClient(httpCli))
 
-			model, err := c.RetrieveModel(tc.modelID)
+			model, err := c.RetrieveModel(context.Background(), tc.modelID)
 			require.NoError(t, err, "failed to retrieve model")
 
 			assert.Equal(t, tc.modelID, model.ID)
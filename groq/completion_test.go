@@ -0,0 +1,115 @@
+package groq
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionalParametersJSON(t *testing.T) {
+	req := ChatCompletionRequest{
+		Model:           ModelIDLLAMA370B,
+		Temperature:     Ptr(0.0),
+		TopP:            Ptr(0.0),
+		PresencePenalty: Ptr(0.0),
+		Seed:            Ptr(0),
+	}
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"messages": null,
+		"model": "llama3-70b-8192",
+		"temperature": 0,
+		"top_p": 0,
+		"presence_penalty": 0,
+		"seed": 0
+	}`, string(data))
+
+	var decoded ChatCompletionRequest
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, req, decoded)
+
+	data, err = json.Marshal(ChatCompletionRequest{Model: ModelIDLLAMA370B})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"messages":null,"model":"llama3-70b-8192"}`, string(data))
+}
+
+func TestRequestExtraFields(t *testing.T) {
+	req := ChatCompletionRequest{
+		Model:    ModelIDLLAMA370B,
+		Messages: []Message{{Role: MessageRoleUser, Content: "hi"}},
+		ExtraFields: map[string]any{
+			"service_tier":     "flex",
+			"reasoning_format": map[string]any{"type": "hidden"},
+		},
+	}
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"messages": [{"role": "user", "content": "hi"}],
+		"model": "llama3-70b-8192",
+		"service_tier": "flex",
+		"reasoning_format": {"type": "hidden"}
+	}`, string(data))
+
+	var decoded ChatCompletionRequest
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, req, decoded)
+
+	req.ExtraFields = map[string]any{"model": "llama3-8b-8192"}
+	data, err = json.Marshal(req)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"model":"llama3-8b-8192"`)
+}
+
+func TestResponseExtra(t *testing.T) {
+	const body = `{
+		"id": "chatcmpl-1",
+		"choices": [{"index": 0, "message": {"role": "assistant", "content": "hi"}, "finish_reason": "stop", "stop_reason": "eos"}],
+		"usage": {"prompt_tokens": 1, "completion_tokens": 1, "total_tokens": 2},
+		"service_tier": "on_demand"
+	}`
+
+	var resp ChatCompletionResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	assert.Equal(t, "chatcmpl-1", resp.ID)
+	assert.Equal(t, map[string]any{"service_tier": "on_demand"}, resp.Extra)
+	assert.Equal(t, "stop", resp.Choices[0].FinishReason)
+	assert.Equal(t, map[string]any{"stop_reason": "eos"}, resp.Choices[0].Extra)
+
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"service_tier":"on_demand"`)
+	assert.Contains(t, string(data), `"stop_reason":"eos"`)
+}
+
+func TestUnknownFields(t *testing.T) {
+	testcases := []struct {
+		name  string
+		data  string
+		extra map[string]any
+	}{
+		{name: "none", data: `{"index":0,"finish_reason":null}`},
+		{name: "nested", data: `{"index":0,"meta":{"tags":["a"]}}`, extra: map[string]any{"meta": map[string]any{"tags": []any{"a"}}}},
+		{name: "case-insensitive match", data: `{"Index":0,"FINISH_REASON":"stop"}`},
+		{name: "unicode case folding", data: `{"finiſh_reason":"stop"}`},
+		{name: "not an object", data: `null`},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			extra, err := unknownFields([]byte(tc.data), Choice{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.extra, extra)
+		})
+	}
+
+	// encoding/json matches the keys unknownFields considers known.
+	var choice Choice
+	require.NoError(t, json.Unmarshal([]byte(`{"finiſh_reason":"stop"}`), &choice))
+	assert.Equal(t, "stop", choice.FinishReason)
+}
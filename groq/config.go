@@ -0,0 +1,265 @@
+package groq
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables read by NewClientFromEnv.
+const (
+	EnvAPIKey       = "GROQ_API_KEY"
+	EnvBaseURL      = "GROQ_BASE_URL"
+	EnvTimeout      = "GROQ_TIMEOUT"     // A Go duration, e.g. "30s"
+	EnvMaxRetries   = "GROQ_MAX_RETRIES" // Number of retries after the first attempt
+	EnvDefaultModel = "GROQ_DEFAULT_MODEL"
+	EnvConfigFile   = "GROQ_CONFIG_FILE" // Path of a JSON or YAML config file
+	EnvProfile      = "GROQ_PROFILE"     // Profile of the config file to use
+)
+
+// Config holds the settings of a Client that can be loaded from a config
+// file or the environment. Zero fields keep the client's defaults.
+type Config struct {
+	APIKey       string
+	BaseURL      string
+	Timeout      time.Duration
+	MaxRetries   int // Number of retries after the first attempt, using DefaultRetryPolicy
+	DefaultModel ModelID
+
+	keySetting string // Setting the API key is read from, named when it's missing
+}
+
+// ConfigError reports an invalid setting. Setting names the environment
+// variable or the config file key the value came from, or where a missing
+// API key can be set.
+type ConfigError struct {
+	Setting string
+	Value   string
+	Err     error
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("groq: invalid %s %q: %v", e.Setting, e.Value, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// configSettings are the settings of a config file or one of its profiles.
+type configSettings struct {
+	APIKey       string `json:"api_key,omitempty" yaml:"api_key,omitempty"`
+	BaseURL      string `json:"base_url,omitempty" yaml:"base_url,omitempty"`
+	Timeout      string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	MaxRetries   *int   `json:"max_retries,omitempty" yaml:"max_retries,omitempty"`
+	DefaultModel string `json:"default_model,omitempty" yaml:"default_model,omitempty"`
+}
+
+// configFile is the content of a config file. Its top-level settings apply
+// to every profile, and a profile's settings override them, e.g.:
+//
+//	timeout: 30s
+//	default_profile: staging
+//	profiles:
+//	  staging:
+//	    base_url: https://groq-gateway.staging.internal/openai
+//	    max_retries: 3
+type configFile struct {
+	configSettings `yaml:",inline"`
+	DefaultProfile string                    `json:"default_profile,omitempty" yaml:"default_profile,omitempty"`
+	Profiles       map[string]configSettings `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+}
+
+// LoadConfig reads the settings of profile from a JSON or YAML config file,
+// chosen by its extension. An empty profile selects the file's
+// default_profile, or only its top-level settings if it has none. Unknown
+// keys are rejected, so misspelled settings aren't silently ignored.
+func LoadConfig(path, profile string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var file configFile
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&file)
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(&file); errors.Is(err, io.EOF) {
+			err = nil // An empty file
+		}
+	default:
+		return Config{}, fmt.Errorf("unsupported config file extension %q: use .json, .yaml or .yml", ext)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	cfg := Config{keySetting: "api_key in " + path}
+	if err := cfg.apply(file.configSettings, func(key string) string { return key }); err != nil {
+		return Config{}, err
+	}
+
+	if profile == "" {
+		profile = file.DefaultProfile
+	}
+	if profile == "" {
+		return cfg, nil
+	}
+
+	settings, ok := file.Profiles[profile]
+	if !ok {
+		return Config{}, &ConfigError{Setting: "profile", Value: profile, Err: fmt.Errorf("not found in %s", path)}
+	}
+	if err := cfg.apply(settings, func(key string) string { return "profiles." + profile + "." + key }); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// ConfigFromEnv returns the configuration from the environment: the profile
+// of the config file named by GROQ_CONFIG_FILE, if any, overridden by the
+// GROQ_* variables that are set.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{keySetting: EnvAPIKey}
+	if path := os.Getenv(EnvConfigFile); path != "" {
+		var err error
+		if cfg, err = LoadConfig(path, os.Getenv(EnvProfile)); err != nil {
+			return Config{}, err
+		}
+		cfg.keySetting = EnvAPIKey + " or " + cfg.keySetting
+	}
+
+	settings := configSettings{
+		APIKey:       os.Getenv(EnvAPIKey),
+		BaseURL:      os.Getenv(EnvBaseURL),
+		Timeout:      os.Getenv(EnvTimeout),
+		DefaultModel: os.Getenv(EnvDefaultModel),
+	}
+	if v := os.Getenv(EnvMaxRetries); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, &ConfigError{Setting: EnvMaxRetries, Value: v, Err: err}
+		}
+		settings.MaxRetries = &n
+	}
+
+	envNames := map[string]string{
+		"api_key":       EnvAPIKey,
+		"base_url":      EnvBaseURL,
+		"timeout":       EnvTimeout,
+		"max_retries":   EnvMaxRetries,
+		"default_model": EnvDefaultModel,
+	}
+	if err := cfg.apply(settings, func(key string) string { return envNames[key] }); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// apply validates the non-empty settings and copies them into cfg. name
+// maps a config file key to the setting name used in errors.
+func (cfg *Config) apply(s configSettings, name func(key string) string) error {
+	if s.APIKey != "" {
+		cfg.APIKey = s.APIKey
+	}
+
+	if s.BaseURL != "" {
+		u, err := url.Parse(s.BaseURL)
+		if err == nil && (u.Scheme != "http" && u.Scheme != "https" || u.Host == "") {
+			err = fmt.Errorf("must be an absolute http or https URL")
+		}
+		if err != nil {
+			return &ConfigError{Setting: name("base_url"), Value: s.BaseURL, Err: err}
+		}
+		cfg.BaseURL = s.BaseURL
+	}
+
+	if s.Timeout != "" {
+		timeout, err := time.ParseDuration(s.Timeout)
+		if err == nil && timeout < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
+		if err != nil {
+			return &ConfigError{Setting: name("timeout"), Value: s.Timeout, Err: err}
+		}
+		cfg.Timeout = timeout
+	}
+
+	if s.MaxRetries != nil {
+		if *s.MaxRetries < 0 {
+			return &ConfigError{Setting: name("max_retries"), Value: strconv.Itoa(*s.MaxRetries), Err: fmt.Errorf("must not be negative")}
+		}
+		cfg.MaxRetries = *s.MaxRetries
+	}
+
+	if s.DefaultModel != "" {
+		cfg.DefaultModel = ModelID(s.DefaultModel)
+	}
+
+	return nil
+}
+
+// Options returns the client options applying cfg.
+func (cfg Config) Options() []Option {
+	var opts []Option
+	if cfg.BaseURL != "" {
+		opts = append(opts, WithBaseURL(cfg.BaseURL))
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, WithTimeout(cfg.Timeout))
+	}
+	if cfg.MaxRetries > 0 {
+		policy := DefaultRetryPolicy()
+		policy.MaxAttempts = cfg.MaxRetries + 1
+		opts = append(opts, WithRetryPolicy(policy))
+	}
+	if cfg.DefaultModel != "" {
+		opts = append(opts, WithDefaultModel(cfg.DefaultModel))
+	}
+
+	return opts
+}
+
+// NewClientFromConfig creates a Client from cfg. Options in opts are applied
+// after cfg, so they take precedence over it.
+func NewClientFromConfig(cfg Config, opts ...Option) (Client, error) {
+	c := NewClient(cfg.APIKey, append(cfg.Options(), opts...)...).(*client)
+	if key, ok := c.keys.(staticKey); ok && key == "" {
+		setting := cfg.keySetting
+		if setting == "" {
+			setting = "APIKey"
+		}
+
+		return nil, &ConfigError{Setting: setting, Err: fmt.Errorf("an API key or a KeyProvider is required")}
+	}
+
+	return c, nil
+}
+
+// NewClientFromEnv creates a Client configured by ConfigFromEnv. Options in
+// opts take precedence over the environment and the config file.
+func NewClientFromEnv(opts ...Option) (Client, error) {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClientFromConfig(cfg, opts...)
+}
@@ -0,0 +1,116 @@
+package groq
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigFile = `
+timeout: 30s
+default_model: llama3-8b-8192
+default_profile: staging
+profiles:
+  staging:
+    base_url: https://groq-gateway.staging.internal/openai
+    max_retries: 3
+  broken:
+    timeout: soon
+`
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfigFile(t, "groq.yaml", testConfigFile)
+
+	cfg, err := LoadConfig(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, Config{
+		BaseURL:      "https://groq-gateway.staging.internal/openai",
+		Timeout:      30 * time.Second,
+		MaxRetries:   3,
+		DefaultModel: ModelIDLLAMA38B,
+		keySetting:   "api_key in " + path,
+	}, cfg)
+
+	_, err = LoadConfig(path, "broken")
+	var cfgErr *ConfigError
+	require.True(t, errors.As(err, &cfgErr), "error should be a *ConfigError")
+	assert.Equal(t, "profiles.broken.timeout", cfgErr.Setting)
+	assert.Equal(t, "soon", cfgErr.Value)
+}
+
+func TestLoadConfigRejectsUnknownKeys(t *testing.T) {
+	testcases := []struct {
+		name    string
+		file    string
+		content string
+		err     string
+	}{
+		{name: "yaml", file: "groq.yaml", content: "base-url: https://groq.internal\n", err: "field base-url not found"},
+		{name: "yaml profile", file: "groq.yaml", content: "profiles:\n  prod:\n    max-retries: 3\n", err: "field max-retries not found"},
+		{name: "json", file: "groq.json", content: `{"base-url":"https://groq.internal"}`, err: `unknown field "base-url"`},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfigFile(t, tc.file, tc.content), "")
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+
+	cfg, err := LoadConfig(writeConfigFile(t, "groq.yaml", ""), "")
+	require.NoError(t, err)
+	assert.Empty(t, cfg.BaseURL)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	path := writeConfigFile(t, "groq.json", `{"profiles":{"prod":{"timeout":"10s","max_retries":1}}}`)
+	t.Setenv(EnvConfigFile, path)
+	t.Setenv(EnvProfile, "prod")
+	t.Setenv(EnvAPIKey, "test-key")
+	t.Setenv(EnvTimeout, "1m")
+
+	cfg, err := ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, Config{APIKey: "test-key", Timeout: time.Minute, MaxRetries: 1, keySetting: EnvAPIKey + " or api_key in " + path}, cfg)
+
+	t.Setenv(EnvBaseURL, "groq.internal")
+	_, err = NewClientFromEnv()
+	var cfgErr *ConfigError
+	require.True(t, errors.As(err, &cfgErr), "error should be a *ConfigError")
+	assert.Equal(t, EnvBaseURL, cfgErr.Setting)
+}
+
+func TestNewClientFromEnvRequiresKey(t *testing.T) {
+	t.Setenv(EnvAPIKey, "")
+
+	_, err := NewClientFromEnv()
+	require.Error(t, err)
+
+	var cfgErr *ConfigError
+	require.True(t, errors.As(err, &cfgErr), "error should be a *ConfigError")
+	assert.Equal(t, EnvAPIKey, cfgErr.Setting)
+
+	_, err = NewClientFromEnv(WithKeyProvider(NewKeyPool([]string{"test-key"})))
+	require.NoError(t, err)
+
+	path := writeConfigFile(t, "groq.yaml", testConfigFile)
+	cfg, err := LoadConfig(path, "")
+	require.NoError(t, err)
+	_, err = NewClientFromConfig(cfg)
+	require.True(t, errors.As(err, &cfgErr), "error should be a *ConfigError")
+	assert.Equal(t, "api_key in "+path, cfgErr.Setting)
+}
@@ -0,0 +1,179 @@
+package groq
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Error codes returned by the Groq API.
+const (
+	ErrorCodeRateLimitExceeded     = "rate_limit_exceeded"
+	ErrorCodeInvalidAPIKey         = "invalid_api_key"
+	ErrorCodeContextLengthExceeded = "context_length_exceeded"
+	ErrorCodeModelNotFound         = "model_not_found"
+	ErrorCodeJSONValidateFailed    = "json_validate_failed"
+)
+
+// APIError is returned when the API responds with a non-2xx status code, or
+// sends an error event in the middle of a stream.
+// Use errors.As to retrieve it from an error returned by the Client.
+type APIError struct {
+	StatusCode int    // HTTP status code of the response; zero for an error event of a stream
+	Stream     bool   // Whether the error was sent as an event of a stream
+	Type       string // Type of the error (e.g., "invalid_request_error")
+	Code       string // Machine-readable error code (e.g., "model_not_found")
+	Message    string // Human-readable description of the error
+	Param      string // Request parameter the error relates to, if any
+	RequestID  string // Value of the x-request-id response header
+	KeyID      string // Redacted form of the API key the request was sent with, when the client rotates keys
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+
+	if e.Stream {
+		b.WriteString("groq: stream error")
+	} else {
+		fmt.Fprintf(&b, "groq: status %d", e.StatusCode)
+	}
+	if e.Code != "" {
+		fmt.Fprintf(&b, " (%s)", e.Code)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " [request id: %s]", e.RequestID)
+	}
+	if e.KeyID != "" {
+		fmt.Fprintf(&b, " [key: %s]", e.KeyID)
+	}
+
+	return b.String()
+}
+
+// JSONValidateError is returned when the model failed to generate JSON
+// matching the requested ResponseFormat. It wraps the APIError.
+type JSONValidateError struct {
+	*APIError
+	// FailedGeneration is the invalid output of the model, for debugging.
+	FailedGeneration string
+}
+
+func (e *JSONValidateError) Unwrap() error {
+	return e.APIError
+}
+
+// errorEnvelope is the body of an error response.
+type errorEnvelope struct {
+	Error struct {
+		Message          string `json:"message"`
+		Type             string `json:"type"`
+		Code             any    `json:"code"`
+		Param            any    `json:"param"`
+		FailedGeneration string `json:"failed_generation"`
+	} `json:"error"`
+}
+
+// newAPIError builds the error of a non-2xx response from its body: an
+// *APIError, or a *JSONValidateError wrapping one. Bodies that aren't an
+// error envelope are kept verbatim as the message. keyID identifies the key
+// the request was sent with, if it's worth telling.
+func newAPIError(resp *http.Response, body []byte, keyID string) error {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("x-request-id"),
+		KeyID:      keyID,
+	}
+
+	var envelope errorEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Error.Message == "" {
+		apiErr.Message = strings.TrimSpace(string(body))
+		if apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+
+		return apiErr
+	}
+
+	apiErr.Type = envelope.Error.Type
+	apiErr.Message = envelope.Error.Message
+	if envelope.Error.Code != nil {
+		apiErr.Code = fmt.Sprint(envelope.Error.Code)
+	}
+	if envelope.Error.Param != nil {
+		apiErr.Param = fmt.Sprint(envelope.Error.Param)
+	}
+
+	if apiErr.Code == ErrorCodeJSONValidateFailed {
+		return &JSONValidateError{APIError: apiErr, FailedGeneration: envelope.Error.FailedGeneration}
+	}
+
+	return apiErr
+}
+
+// newStreamAPIError builds the error of an error event received in the event
+// stream of resp, with the event's data. Its StatusCode is left at zero, as
+// the response itself was accepted.
+func newStreamAPIError(resp *http.Response, data []byte, keyID string) error {
+	err := newAPIError(resp, data, keyID)
+	if apiErr, ok := asAPIError(err); ok {
+		apiErr.StatusCode, apiErr.Stream = 0, true
+	}
+
+	return err
+}
+
+// asAPIError returns the APIError in err's chain, if any.
+func asAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	ok := errors.As(err, &apiErr)
+
+	return apiErr, ok
+}
+
+// IsRateLimited reports whether err is an API error caused by a rate limit.
+func IsRateLimited(err error) bool {
+	apiErr, ok := asAPIError(err)
+
+	return ok && (apiErr.StatusCode == http.StatusTooManyRequests || apiErr.Code == ErrorCodeRateLimitExceeded)
+}
+
+// IsAuthError reports whether err is an API error caused by a missing,
+// invalid or unauthorized API key.
+func IsAuthError(err error) bool {
+	apiErr, ok := asAPIError(err)
+
+	return ok && (apiErr.StatusCode == http.StatusUnauthorized ||
+		apiErr.StatusCode == http.StatusForbidden ||
+		apiErr.Code == ErrorCodeInvalidAPIKey)
+}
+
+// IsContextLengthExceeded reports whether err is an API error caused by a
+// request that doesn't fit in the model's context window.
+func IsContextLengthExceeded(err error) bool {
+	apiErr, ok := asAPIError(err)
+
+	return ok && apiErr.Code == ErrorCodeContextLengthExceeded
+}
+
+// IsModelNotFound reports whether err is an API error caused by an unknown
+// or inaccessible model.
+func IsModelNotFound(err error) bool {
+	apiErr, ok := asAPIError(err)
+
+	return ok && (apiErr.Code == ErrorCodeModelNotFound ||
+		apiErr.StatusCode == http.StatusNotFound && apiErr.Param == "model")
+}
+
+// IsJSONValidateFailed reports whether err is an API error caused by the
+// model failing to generate valid JSON. Use errors.As with a
+// *JSONValidateError to get the failed generation.
+func IsJSONValidateFailed(err error) bool {
+	apiErr, ok := asAPIError(err)
+
+	return ok && apiErr.Code == ErrorCodeJSONValidateFailed
+}
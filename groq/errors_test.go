@@ -0,0 +1,56 @@
+package groq
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("x-request-id", "req_123")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"message":"The model does not exist","type":"invalid_request_error","code":"model_not_found","param":null}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("test-key", WithBaseURL(server.URL))
+
+	_, err := c.RetrieveModel(context.Background(), "unknown")
+	require.Error(t, err)
+
+	apiErr, ok := asAPIError(err)
+	require.True(t, ok, "error should be an *APIError")
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.False(t, apiErr.Stream)
+	assert.Equal(t, "invalid_request_error", apiErr.Type)
+	assert.Equal(t, ErrorCodeModelNotFound, apiErr.Code)
+	assert.Equal(t, "The model does not exist", apiErr.Message)
+	assert.Empty(t, apiErr.Param)
+	assert.Equal(t, "req_123", apiErr.RequestID)
+	assert.Empty(t, apiErr.KeyID, "a single key needs no naming")
+
+	assert.True(t, IsModelNotFound(err))
+	assert.False(t, IsRateLimited(err))
+	assert.False(t, IsAuthError(err))
+}
+
+func TestAPIErrorWithoutEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "upstream unavailable", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	c := NewClient("test-key", WithBaseURL(server.URL))
+
+	_, err := c.ListModels(context.Background())
+
+	apiErr, ok := asAPIError(err)
+	require.True(t, ok, "error should be an *APIError")
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	assert.Equal(t, "upstream unavailable", apiErr.Message)
+}
@@ -0,0 +1,128 @@
+package groq
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// jsonFieldsCache maps struct types to the set of their JSON field names.
+var jsonFieldsCache sync.Map
+
+// jsonFields returns the JSON names of the fields of struct type t, folded by
+// foldName as encoding/json matches them.
+func jsonFields(t reflect.Type) map[string]bool {
+	if fields, ok := jsonFieldsCache.Load(t); ok {
+		return fields.(map[string]bool)
+	}
+
+	fields := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch {
+		case name == "-":
+		case f.Anonymous && name == "":
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			for name := range jsonFields(embedded) {
+				fields[name] = true
+			}
+		case f.IsExported():
+			if name == "" {
+				name = f.Name
+			}
+			fields[foldName(name)] = true
+		}
+	}
+	jsonFieldsCache.Store(t, fields)
+
+	return fields
+}
+
+// marshalWithExtra marshals v, a struct without a MarshalJSON method, and
+// merges extra into the resulting object. Extra fields take precedence over
+// the fields of v.
+func marshalWithExtra(v any, extra map[string]any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	for key, value := range extra {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		object[key] = raw
+	}
+
+	return json.Marshal(object)
+}
+
+// unknownFields returns the fields of the JSON object data that aren't fields
+// of struct v, or nil if there are none. Only their values are decoded.
+func unknownFields(data []byte, v any) (map[string]any, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+
+	known := jsonFields(reflect.TypeOf(v))
+	var extra map[string]any
+	for key, raw := range object {
+		if known[foldName(key)] {
+			continue
+		}
+
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		if extra == nil {
+			extra = make(map[string]any)
+		}
+		extra[key] = value
+	}
+
+	return extra, nil
+}
+
+// foldName folds the case of a JSON key as encoding/json does to match it
+// with a field name.
+func foldName(name string) string {
+	var b strings.Builder
+	b.Grow(len(name))
+	for _, r := range name {
+		if r < utf8.RuneSelf {
+			if 'a' <= r && r <= 'z' {
+				r -= 'a' - 'A'
+			}
+		} else {
+			r = foldRune(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// foldRune returns the smallest rune of the case folding set of r.
+func foldRune(r rune) rune {
+	for {
+		next := unicode.SimpleFold(r)
+		if next <= r {
+			return next
+		}
+		r = next
+	}
+}
@@ -0,0 +1,77 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newEventStreamServer answers every request with an event stream of events,
+// each the fields of one server-sent event such as "data: [DONE]".
+func newEventStreamServer(t *testing.T, events ...string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			fmt.Fprint(w, event+"\n\n")
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// newStallingServer streams deltas, each after delay, then stalls until the
+// client goes away.
+func newStallingServer(t *testing.T, delay time.Duration, deltas ...string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"role":"assistant"}}]}`+"\n\n")
+		w.(http.Flusher).Flush()
+
+		for _, delta := range deltas {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(delay):
+			}
+			fmt.Fprint(w, contentEvent(delta)+"\n\n")
+			w.(http.Flusher).Flush()
+		}
+
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// contentEvent is the event of a chunk adding content to the first choice.
+func contentEvent(content string) string {
+	return fmt.Sprintf(`data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":%q}}]}`, content)
+}
+
+// openStream opens a stream of req on a client of server configured by opts.
+// A request without messages says "hi". The stream is closed when the test
+// ends.
+func openStream(t *testing.T, server *httptest.Server, req ChatCompletionRequest, opts ...Option) *ChatCompletionStream {
+	t.Helper()
+
+	if len(req.Messages) == 0 {
+		req.Messages = []Message{{Role: MessageRoleUser, Content: "hi"}}
+	}
+	req.Stream = true
+
+	c := NewClient("test-key", append([]Option{WithBaseURL(server.URL)}, opts...)...)
+	stream, err := c.CreateChatCompletionStream(context.Background(), req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = stream.Close() })
+
+	return stream
+}
@@ -0,0 +1,72 @@
+package groq
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+)
+
+// MaxImageSize is the maximum size of a base64 encoded image accepted by the
+// API.
+const MaxImageSize = 4 << 20
+
+var (
+	// ErrImageTooLarge is returned for images larger than MaxImageSize once
+	// base64 encoded.
+	ErrImageTooLarge = errors.New("groq: image exceeds the maximum size of base64 encoded images")
+	// ErrUnsupportedImageType is returned for data that isn't a JPEG, PNG,
+	// GIF or WebP image.
+	ErrUnsupportedImageType = errors.New("groq: unsupported image type")
+)
+
+var supportedImageTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// NewImagePartFromBytes returns an image ContentPart embedding data as a
+// base64 data URL. The image type is sniffed from data.
+func NewImagePartFromBytes(data []byte) (ContentPart, error) {
+	if base64.StdEncoding.EncodedLen(len(data)) > MaxImageSize {
+		return ContentPart{}, ErrImageTooLarge
+	}
+
+	mimeType := http.DetectContentType(data)
+	if !slices.Contains(supportedImageTypes, mimeType) {
+		return ContentPart{}, fmt.Errorf("%w: %s", ErrUnsupportedImageType, mimeType)
+	}
+
+	return NewImageURLPart("data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)), nil
+}
+
+// NewImagePartFromReader returns an image ContentPart embedding the image
+// read from r as a base64 data URL. It stops reading once the image is known
+// to be too large.
+func NewImagePartFromReader(r io.Reader) (ContentPart, error) {
+	maxBytes := int64(base64.StdEncoding.DecodedLen(MaxImageSize))
+
+	data, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return ContentPart{}, fmt.Errorf("failed to read image: %w", err)
+	}
+	if int64(len(data)) > maxBytes {
+		return ContentPart{}, ErrImageTooLarge
+	}
+
+	return NewImagePartFromBytes(data)
+}
+
+// NewImagePartFromFile returns an image ContentPart embedding the image file
+// at path as a base64 data URL.
+func NewImagePartFromFile(path string) (ContentPart, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ContentPart{}, fmt.Errorf("failed to open image: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	return NewImagePartFromReader(f)
+}
@@ -0,0 +1,220 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultKeyCooldown = time.Minute
+
+// KeyProvider supplies the API key of each request, e.g. to spread load over
+// several keys. Implementations must be safe for concurrent use.
+type KeyProvider interface {
+	// Key returns the key to authenticate the next request with.
+	Key(ctx context.Context) (string, error)
+	// Report records the outcome of a request authenticated with key: the
+	// status code and headers of its response, or zero and nil if no
+	// response arrived.
+	Report(key string, statusCode int, header http.Header)
+}
+
+// staticKey is the KeyProvider of a client created with a single API key.
+type staticKey string
+
+func (k staticKey) Key(context.Context) (string, error) {
+	return string(k), nil
+}
+
+func (staticKey) Report(string, int, http.Header) {}
+
+// KeyPoolStrategy selects the next key of a KeyPool.
+type KeyPoolStrategy int
+
+const (
+	// KeyPoolRoundRobin uses the available keys in turn.
+	KeyPoolRoundRobin KeyPoolStrategy = iota
+	// KeyPoolLeastUsed uses the available key that served the fewest requests.
+	KeyPoolLeastUsed
+)
+
+// ErrNoKeys is returned by a KeyPool that was created without keys.
+var ErrNoKeys = errors.New("groq: key pool has no keys")
+
+// KeyPool is a KeyProvider rotating over several API keys. A key that is
+// rejected with 401 or rate limited with 429 is taken out of rotation until
+// its rate limit resets, as reported by the response headers, or until the
+// pool's cooldown has passed. When every key is out of rotation, Key waits
+// for the first one to come back.
+type KeyPool struct {
+	mu       sync.Mutex
+	keys     []*pooledKey
+	strategy KeyPoolStrategy
+	cooldown time.Duration
+	next     int
+	now      func() time.Time
+}
+
+type pooledKey struct {
+	key           string
+	uses          int
+	failures      int
+	disabledUntil time.Time
+}
+
+// KeyPoolOption configures a KeyPool created by NewKeyPool.
+type KeyPoolOption func(*KeyPool)
+
+// WithKeyPoolStrategy sets how the pool selects the next key.
+// Defaults to KeyPoolRoundRobin.
+func WithKeyPoolStrategy(strategy KeyPoolStrategy) KeyPoolOption {
+	return func(p *KeyPool) {
+		p.strategy = strategy
+	}
+}
+
+// WithKeyCooldown sets how long a failing key stays out of rotation when the
+// response doesn't say when its limit resets. Defaults to one minute.
+func WithKeyCooldown(cooldown time.Duration) KeyPoolOption {
+	return func(p *KeyPool) {
+		p.cooldown = cooldown
+	}
+}
+
+// NewKeyPool creates a KeyPool rotating over keys.
+func NewKeyPool(keys []string, opts ...KeyPoolOption) *KeyPool {
+	p := &KeyPool{
+		cooldown: defaultKeyCooldown,
+		now:      time.Now,
+	}
+	for _, key := range keys {
+		p.keys = append(p.keys, &pooledKey{key: key})
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Key returns the next available key, waiting for one to come back into
+// rotation if necessary.
+func (p *KeyPool) Key(ctx context.Context) (string, error) {
+	for {
+		key, wait, err := p.pick()
+		if err != nil || wait == 0 {
+			return key, err
+		}
+
+		if err := sleep(ctx, wait); err != nil {
+			return "", err
+		}
+	}
+}
+
+// pick selects an available key, or returns how long until one is available.
+func (p *KeyPool) pick() (string, time.Duration, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.keys) == 0 {
+		return "", 0, ErrNoKeys
+	}
+
+	now := p.now()
+
+	var (
+		picked *pooledKey
+		wait   time.Duration
+	)
+	for i := range p.keys {
+		k := p.keys[(p.next+i)%len(p.keys)]
+		if now.Before(k.disabledUntil) {
+			if d := k.disabledUntil.Sub(now); wait == 0 || d < wait {
+				wait = d
+			}
+			continue
+		}
+
+		if picked == nil || p.strategy == KeyPoolLeastUsed && k.uses < picked.uses {
+			picked = k
+		}
+		if p.strategy == KeyPoolRoundRobin {
+			p.next = (p.next + i + 1) % len(p.keys)
+			break
+		}
+	}
+
+	if picked == nil {
+		return "", wait, nil
+	}
+	picked.uses++
+
+	return picked.key, 0, nil
+}
+
+// Report takes key out of rotation if it was rejected or rate limited.
+func (p *KeyPool) Report(key string, statusCode int, header http.Header) {
+	if statusCode != http.StatusUnauthorized && statusCode != http.StatusTooManyRequests {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, k := range p.keys {
+		if k.key != key {
+			continue
+		}
+
+		cooldown := p.cooldown
+		if d, ok := retryAfter(header); ok && statusCode == http.StatusTooManyRequests {
+			cooldown = d
+		}
+		k.failures++
+		k.disabledUntil = p.now().Add(cooldown)
+	}
+}
+
+// KeyStats is the health of a key in a KeyPool.
+type KeyStats struct {
+	KeyID         string    // Redacted form of the key, as in ResponseMeta.KeyID
+	Uses          int       // Number of requests the key was handed out for
+	Failures      int       // Number of 401 and 429 responses to the key
+	Available     bool      // Whether the key is in rotation
+	DisabledUntil time.Time // When the key comes back into rotation, if it's out
+}
+
+// Stats returns the health of every key in the pool, in the order they were
+// given to NewKeyPool.
+func (p *KeyPool) Stats() []KeyStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	stats := make([]KeyStats, 0, len(p.keys))
+	for _, k := range p.keys {
+		stats = append(stats, KeyStats{
+			KeyID:         redactKey(k.key),
+			Uses:          k.uses,
+			Failures:      k.failures,
+			Available:     !now.Before(k.disabledUntil),
+			DisabledUntil: k.disabledUntil,
+		})
+	}
+
+	return stats
+}
+
+// redactKey identifies key without revealing it, e.g. "gsk_…x9Qa".
+func redactKey(key string) string {
+	const visible = 4
+	if len(key) <= 2*visible {
+		return "…"
+	}
+
+	return key[:visible] + "…" + key[len(key)-visible:]
+}
@@ -0,0 +1,115 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyPoolRoundRobin(t *testing.T) {
+	now := time.Unix(0, 0)
+	pool := NewKeyPool([]string{"key-a", "key-b", "key-c"})
+	pool.now = func() time.Time { return now }
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		key, err := pool.Key(context.Background())
+		require.NoError(t, err)
+		got = append(got, key)
+	}
+	assert.Equal(t, []string{"key-a", "key-b", "key-c", "key-a"}, got)
+
+	pool.Report("key-b", http.StatusTooManyRequests, http.Header{"Retry-After": []string{"30"}})
+	for i := 0; i < 3; i++ {
+		key, err := pool.Key(context.Background())
+		require.NoError(t, err)
+		assert.NotEqual(t, "key-b", key, "rate limited key must be out of rotation")
+	}
+
+	stats := pool.Stats()
+	assert.False(t, stats[1].Available)
+	assert.Equal(t, now.Add(30*time.Second), stats[1].DisabledUntil)
+
+	now = now.Add(30 * time.Second)
+	assert.True(t, pool.Stats()[1].Available, "key should be back after its reset")
+}
+
+func TestKeyPoolLeastUsed(t *testing.T) {
+	pool := NewKeyPool([]string{"key-a", "key-b"}, WithKeyPoolStrategy(KeyPoolLeastUsed))
+	pool.keys[0].uses = 2
+
+	key, err := pool.Key(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "key-b", key)
+}
+
+func TestKeyPoolRetriesWithNextKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer gsk_first_key_0001" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(ListModelsResponse{ObjectType: "list"})
+	}))
+	defer server.Close()
+
+	pool := NewKeyPool([]string{"gsk_first_key_0001", "gsk_second_key_0002"})
+	c := NewClient("", WithBaseURL(server.URL), WithKeyProvider(pool), WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: time.Millisecond,
+	}))
+
+	models, err := c.ListModels(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "gsk_…0002", models.Meta.KeyID)
+	assert.Equal(t, 1, pool.Stats()[0].Failures)
+}
+
+func TestKeyPoolRetriesWithoutWaitingForReset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer gsk_first_key_0001" {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(ListModelsResponse{ObjectType: "list"})
+	}))
+	defer server.Close()
+
+	pool := NewKeyPool([]string{"gsk_first_key_0001", "gsk_second_key_0002"})
+	c := NewClient("", WithBaseURL(server.URL), WithKeyProvider(pool), WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: time.Millisecond,
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	models, err := c.ListModels(ctx)
+	require.NoError(t, err, "the retry must not wait for the first key's reset")
+	assert.Equal(t, "gsk_…0002", models.Meta.KeyID)
+}
+
+func TestKeyPoolErrorNamesKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	pool := NewKeyPool([]string{"gsk_first_key_0001"})
+	c := NewClient("", WithBaseURL(server.URL), WithKeyProvider(pool))
+
+	_, err := c.ListModels(context.Background())
+	apiErr, ok := asAPIError(err)
+	require.True(t, ok, "error should be an *APIError")
+	assert.Equal(t, "gsk_…0001", apiErr.KeyID)
+	assert.EqualError(t, err, "groq: status 401: Unauthorized [key: gsk_…0001]")
+}
@@ -0,0 +1,105 @@
+package groq
+
+import (
+	"context"
+	"sync"
+)
+
+// LegacyClient is the context-free API of earlier releases. Requests made
+// through it run with context.Background and are only bounded by the client's
+// timeout.
+//
+// Deprecated: Use Client, which accepts a context.Context in every method.
+type LegacyClient interface {
+	CreateChatCompletion(ChatCompletionRequest) (*ChatCompletionResponse, error)
+	CreateChatCompletionStream(context.Context, ChatCompletionRequest) (<-chan *ChatCompletionStreamResponse, func(), error)
+	ListModels() (*ListModelsResponse, error)
+	RetrieveModel(ModelID) (*Model, error)
+}
+
+// ChatCompletionStreamResponse is a chunk, or the error ending the stream,
+// delivered on the channel of LegacyClient.CreateChatCompletionStream.
+//
+// Deprecated: Use the ChatCompletionStream returned by
+// Client.CreateChatCompletionStream.
+type ChatCompletionStreamResponse struct {
+	Response ChatCompletionResponse
+	Error    error
+
+	RateLimit *RateLimitInfo // Rate limit state reported when the stream was opened
+	Meta      *ResponseMeta  // HTTP response the stream is read from
+	Usage     *Usage         // Usage of the whole request, set on the last response of the stream
+}
+
+var _ LegacyClient = legacyClient{}
+
+// NewLegacyClient creates a client with the context-free API of earlier releases.
+//
+// Deprecated: Use NewClient.
+func NewLegacyClient(apiKey string, opts ...Option) LegacyClient {
+	return NewLegacy(NewClient(apiKey, opts...))
+}
+
+// NewLegacy adapts c to the LegacyClient interface.
+//
+// Deprecated: Call the context-aware methods of Client directly.
+func NewLegacy(c Client) LegacyClient {
+	return legacyClient{c: c}
+}
+
+type legacyClient struct {
+	c Client
+}
+
+func (l legacyClient) CreateChatCompletion(req ChatCompletionRequest) (*ChatCompletionResponse, error) {
+	return l.c.CreateChatCompletion(context.Background(), req)
+}
+
+func (l legacyClient) CreateChatCompletionStream(ctx context.Context, req ChatCompletionRequest) (<-chan *ChatCompletionStreamResponse, func(), error) {
+	stream, err := l.c.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	responseCh := make(chan *ChatCompletionStreamResponse)
+	done := make(chan struct{})
+	go func() {
+		defer close(responseCh)
+		defer func() {
+			_ = stream.Close()
+		}()
+
+		for chunk, err := range stream.All() {
+			resp := &ChatCompletionStreamResponse{Error: err}
+			if chunk != nil {
+				resp.Response, resp.RateLimit, resp.Meta, resp.Usage = *chunk, chunk.RateLimit, chunk.Meta, chunk.streamUsage()
+			}
+
+			select {
+			case responseCh <- resp:
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	closer := func() {
+		once.Do(func() {
+			close(done)
+			_ = stream.Close()
+		})
+	}
+
+	return responseCh, closer, nil
+}
+
+func (l legacyClient) ListModels() (*ListModelsResponse, error) {
+	return l.c.ListModels(context.Background())
+}
+
+func (l legacyClient) RetrieveModel(id ModelID) (*Model, error) {
+	return l.c.RetrieveModel(context.Background(), id)
+}
@@ -0,0 +1,254 @@
+package groq
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Budget is the request and token allowance of a model. Zero fields are
+// unlimited.
+type Budget struct {
+	RequestsPerMinute int // Maximum number of requests per minute
+	RequestsPerDay    int // Maximum number of requests per day
+	TokensPerMinute   int // Maximum number of prompt and completion tokens per minute
+	TokensPerDay      int // Maximum number of prompt and completion tokens per day
+}
+
+// Limiter paces requests on the client side so they stay within the budget
+// of their model instead of failing with 429. Each budget is enforced by a
+// token bucket that refills continuously over its window.
+//
+// A Limiter is safe for concurrent use and should be shared by every client
+// that uses the same API key.
+type Limiter struct {
+	mu      sync.Mutex
+	budgets map[ModelID]Budget
+	buckets map[ModelID][]*bucket
+	now     func() time.Time
+}
+
+// NewLimiter creates a Limiter enforcing the budget of each model.
+// Requests for models without a budget are never delayed.
+func NewLimiter(budgets map[ModelID]Budget) *Limiter {
+	l := &Limiter{
+		budgets: make(map[ModelID]Budget, len(budgets)),
+		buckets: make(map[ModelID][]*bucket, len(budgets)),
+		now:     time.Now,
+	}
+	for model, budget := range budgets {
+		l.budgets[model] = budget
+	}
+
+	return l
+}
+
+// Reservation is the capacity taken by a request admitted by Limiter.Wait.
+type Reservation struct {
+	limiter *Limiter
+	charges []charge
+}
+
+// charge is the number of tokens a reservation took from a token bucket.
+type charge struct {
+	bucket *bucket
+	tokens int
+}
+
+// Wait blocks until the budget of model has room for one request using
+// tokens tokens, then takes that capacity. It returns the context's error if
+// ctx is done first.
+func (l *Limiter) Wait(ctx context.Context, model ModelID, tokens int) (*Reservation, error) {
+	for {
+		charges, delay, ok := l.reserve(model, tokens)
+		if ok {
+			return &Reservation{limiter: l, charges: charges}, nil
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Reconcile corrects the reservation with the number of tokens the request
+// actually used, as reported by Usage.TotalTokens. Overestimates are given
+// back; underestimates are taken from the budget, possibly pushing it into
+// debt that delays later requests.
+func (r *Reservation) Reconcile(tokens int) {
+	if r == nil {
+		return
+	}
+
+	r.limiter.mu.Lock()
+	defer r.limiter.mu.Unlock()
+
+	now := r.limiter.now()
+	for i := range r.charges {
+		c := &r.charges[i]
+		c.bucket.take(now, float64(tokens-c.tokens))
+		c.tokens = tokens
+	}
+}
+
+// reserve takes capacity for a request if every bucket of model has room
+// for it, returning what it took from each token bucket, and otherwise
+// returns how long to wait before trying again.
+func (l *Limiter) reserve(model ModelID, tokens int) ([]charge, time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	buckets := l.bucketsFor(model)
+	if len(buckets) == 0 {
+		return nil, 0, true
+	}
+
+	now := l.now()
+
+	var delay time.Duration
+	for _, b := range buckets {
+		delay = max(delay, b.wait(now, b.cost(tokens)))
+	}
+	if delay > 0 {
+		return nil, delay, false
+	}
+
+	var charges []charge
+	for _, b := range buckets {
+		cost := b.cost(tokens)
+		b.take(now, float64(cost))
+		if b.countsTokens {
+			charges = append(charges, charge{bucket: b, tokens: cost})
+		}
+	}
+
+	return charges, 0, true
+}
+
+// bucketsFor returns the buckets enforcing the budget of model, creating
+// them on first use. l.mu must be held.
+func (l *Limiter) bucketsFor(model ModelID) []*bucket {
+	if buckets, ok := l.buckets[model]; ok {
+		return buckets
+	}
+
+	budget, ok := l.budgets[model]
+	if !ok {
+		return nil
+	}
+
+	now := l.now()
+	var buckets []*bucket
+	for _, limit := range []struct {
+		capacity     int
+		window       time.Duration
+		countsTokens bool
+	}{
+		{budget.RequestsPerMinute, time.Minute, false},
+		{budget.RequestsPerDay, 24 * time.Hour, false},
+		{budget.TokensPerMinute, time.Minute, true},
+		{budget.TokensPerDay, 24 * time.Hour, true},
+	} {
+		if limit.capacity <= 0 {
+			continue
+		}
+		buckets = append(buckets, &bucket{
+			capacity:     float64(limit.capacity),
+			rate:         float64(limit.capacity) / limit.window.Seconds(),
+			level:        float64(limit.capacity),
+			updated:      now,
+			countsTokens: limit.countsTokens,
+		})
+	}
+	l.buckets[model] = buckets
+
+	return buckets
+}
+
+// bucket is a token bucket holding up to capacity units and refilling at
+// rate units per second. Its level may go negative after a reconciliation.
+type bucket struct {
+	capacity     float64
+	rate         float64
+	level        float64
+	updated      time.Time
+	countsTokens bool
+}
+
+// cost returns the number of units a request using tokens tokens takes.
+// Requests larger than the bucket only need it to be full, so they are
+// delayed rather than blocked forever.
+func (b *bucket) cost(tokens int) int {
+	if !b.countsTokens {
+		return 1
+	}
+
+	return min(tokens, int(b.capacity))
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updated).Seconds()
+	if elapsed > 0 {
+		b.level = math.Min(b.capacity, b.level+elapsed*b.rate)
+		b.updated = now
+	}
+}
+
+// wait returns how long until the bucket holds n units.
+func (b *bucket) wait(now time.Time, n int) time.Duration {
+	b.refill(now)
+
+	missing := float64(n) - b.level
+	if missing <= 0 {
+		return 0
+	}
+
+	return time.Duration(math.Ceil(missing / b.rate * float64(time.Second)))
+}
+
+func (b *bucket) take(now time.Time, n float64) {
+	b.refill(now)
+	b.level = math.Min(b.capacity, b.level-n)
+}
+
+// EstimateTokens estimates the number of tokens req counts against a token
+// budget: roughly four characters per prompt token plus a small overhead
+// per message, plus MaxTokens for the completion.
+func EstimateTokens(req ChatCompletionRequest) int {
+	return estimatePromptTokens(req.Messages) + req.MaxTokens
+}
+
+// estimatePromptTokens estimates the number of prompt tokens of messages.
+func estimatePromptTokens(messages []Message) int {
+	const tokensPerMessage = 4
+
+	tokens := 0
+	for _, message := range messages {
+		chars := len(message.Content)
+		for _, part := range message.ContentParts {
+			chars += len(part.Text)
+		}
+		tokens += tokensPerMessage + estimateTextTokens(chars+toolCallChars(message.ToolCalls))
+	}
+
+	return tokens
+}
+
+// estimateTextTokens estimates the number of tokens of chars characters of
+// text.
+func estimateTextTokens(chars int) int {
+	const charsPerToken = 4
+
+	return (chars + charsPerToken - 1) / charsPerToken
+}
+
+// toolCallChars returns the number of characters of calls.
+func toolCallChars(calls []ToolCall) int {
+	var chars int
+	for _, call := range calls {
+		chars += len(call.Function.Name) + len(call.Function.Arguments)
+	}
+
+	return chars
+}
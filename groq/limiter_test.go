@@ -0,0 +1,91 @@
+package groq
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewLimiter(map[ModelID]Budget{
+		ModelIDLLAMA38B: {RequestsPerMinute: 2, TokensPerMinute: 600},
+	})
+	l.now = func() time.Time { return now }
+
+	r, err := l.Wait(context.Background(), ModelIDLLAMA38B, 500)
+	require.NoError(t, err)
+
+	_, delay, ok := l.reserve(ModelIDLLAMA38B, 200)
+	assert.False(t, ok, "token budget should be exhausted")
+	assert.Equal(t, 10*time.Second, delay)
+
+	r.Reconcile(300)
+	_, _, ok = l.reserve(ModelIDLLAMA38B, 200)
+	assert.True(t, ok, "reconciled tokens should be given back")
+
+	_, delay, ok = l.reserve(ModelIDLLAMA38B, 1)
+	assert.False(t, ok, "request budget should be exhausted")
+	assert.Equal(t, 30*time.Second, delay)
+
+	_, _, ok = l.reserve(ModelIDGEMMA, 1_000_000)
+	assert.True(t, ok, "models without a budget are unlimited")
+}
+
+func TestLimiterReconcilesEstimateOverCapacity(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewLimiter(map[ModelID]Budget{ModelIDLLAMA38B: {TokensPerMinute: 6000}})
+	l.now = func() time.Time { return now }
+
+	r, err := l.Wait(context.Background(), ModelIDLLAMA38B, 8200)
+	require.NoError(t, err)
+
+	// Only the 6000 tokens taken are reconciled, leaving 3000 available.
+	r.Reconcile(3000)
+	_, _, ok := l.reserve(ModelIDLLAMA38B, 3000)
+	assert.True(t, ok)
+	_, _, ok = l.reserve(ModelIDLLAMA38B, 1)
+	assert.False(t, ok, "the used tokens must stay taken")
+}
+
+func TestLimiterWaitHonorsContext(t *testing.T) {
+	l := NewLimiter(map[ModelID]Budget{ModelIDLLAMA38B: {RequestsPerDay: 1}})
+
+	_, err := l.Wait(context.Background(), ModelIDLLAMA38B, 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = l.Wait(ctx, ModelIDLLAMA38B, 0)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestStreamReconcilesWithoutUsage(t *testing.T) {
+	server := newEventStreamServer(t, contentEvent("Hello world!"), "data: [DONE]")
+
+	now := time.Unix(0, 0)
+	l := NewLimiter(map[ModelID]Budget{ModelIDLLAMA38B: {TokensPerMinute: 1000}})
+	l.now = func() time.Time { return now }
+
+	stream := openStream(t, server, ChatCompletionRequest{Model: ModelIDLLAMA38B, MaxTokens: 900}, WithLimiter(l))
+
+	_, _, ok := l.reserve(ModelIDLLAMA38B, 500)
+	assert.False(t, ok, "the stream should hold its estimate while open")
+
+	for stream.Next() {
+		assert.Equal(t, "Hello world!", stream.Current().Choices[0].Delta.Content)
+	}
+	require.NoError(t, stream.Err())
+	assert.Nil(t, stream.Usage())
+
+	// The prompt estimate of 5 tokens plus 3 tokens for the 12 generated
+	// characters are kept; the rest of MaxTokens is given back.
+	_, _, ok = l.reserve(ModelIDLLAMA38B, 992)
+	assert.True(t, ok, "unused tokens should be given back")
+	_, _, ok = l.reserve(ModelIDLLAMA38B, 1)
+	assert.False(t, ok)
+}
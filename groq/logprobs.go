@@ -0,0 +1,87 @@
+package groq
+
+import (
+	"math"
+	"sort"
+)
+
+// Logprobs holds the log probabilities of the tokens of a choice, returned
+// when ChatCompletionRequest.Logprobs is set.
+type Logprobs struct {
+	Content []TokenLogprob `json:"content"` // Log probability of each token of the message content
+}
+
+// TokenLogprob is the log probability of a generated token.
+type TokenLogprob struct {
+	Token       string       `json:"token"`        // The token
+	Logprob     float64      `json:"logprob"`      // Log probability of the token
+	Bytes       []int        `json:"bytes"`        // UTF-8 bytes of the token, for tokens that aren't valid UTF-8 on their own
+	TopLogprobs []TopLogprob `json:"top_logprobs"` // Most likely tokens at this position, up to ChatCompletionRequest.TopLogprobs
+}
+
+// TopLogprob is the log probability of a likely token at a position.
+type TopLogprob struct {
+	Token   string  `json:"token"`   // The token
+	Logprob float64 `json:"logprob"` // Log probability of the token
+	Bytes   []int   `json:"bytes"`   // UTF-8 bytes of the token
+}
+
+// Probability returns the probability of the token, between 0 and 1.
+func (t TokenLogprob) Probability() float64 {
+	return math.Exp(t.Logprob)
+}
+
+// Probability returns the probability of the token, between 0 and 1.
+func (t TopLogprob) Probability() float64 {
+	return math.Exp(t.Logprob)
+}
+
+// SequenceLogprob returns the log probability of the whole sequence of
+// tokens, the sum of their log probabilities.
+func (l *Logprobs) SequenceLogprob() float64 {
+	if l == nil {
+		return 0
+	}
+
+	var sum float64
+	for _, t := range l.Content {
+		sum += t.Logprob
+	}
+
+	return sum
+}
+
+// SequenceProbability returns the probability of the whole sequence of
+// tokens, between 0 and 1.
+func (l *Logprobs) SequenceProbability() float64 {
+	return math.Exp(l.SequenceLogprob())
+}
+
+// TopAlternatives returns, for each position, at most n of the most likely
+// tokens other than the generated one, most likely first. A negative n
+// returns all of them.
+func (l *Logprobs) TopAlternatives(n int) [][]TopLogprob {
+	if l == nil {
+		return nil
+	}
+
+	alternatives := make([][]TopLogprob, len(l.Content))
+	for i, t := range l.Content {
+		alts := make([]TopLogprob, 0, len(t.TopLogprobs))
+		for _, top := range t.TopLogprobs {
+			if top.Token != t.Token {
+				alts = append(alts, top)
+			}
+		}
+
+		sort.SliceStable(alts, func(i, j int) bool {
+			return alts[i].Logprob > alts[j].Logprob
+		})
+		if n >= 0 && len(alts) > n {
+			alts = alts[:n]
+		}
+		alternatives[i] = alts
+	}
+
+	return alternatives
+}
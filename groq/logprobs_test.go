@@ -0,0 +1,63 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogprobs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatCompletionRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.True(t, req.Logprobs)
+		assert.Equal(t, Ptr(2), req.TopLogprobs)
+
+		_, _ = w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"yes."},"logprobs":{"content":[
+			{"token":"yes","logprob":-0.1,"bytes":[121,101,115],"top_logprobs":[
+				{"token":"no","logprob":-2.5,"bytes":[110,111]},
+				{"token":"yes","logprob":-0.1,"bytes":[121,101,115]},
+				{"token":"maybe","logprob":-3,"bytes":[109,97,121,98,101]}
+			]},
+			{"token":".","logprob":-0.2,"bytes":[46],"top_logprobs":[{"token":".","logprob":-0.2,"bytes":[46]}]}
+		]},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("test-key", WithBaseURL(server.URL))
+
+	resp, err := c.CreateChatCompletion(context.Background(), ChatCompletionRequest{
+		Model:       ModelIDLLAMA370B,
+		Messages:    []Message{{Role: MessageRoleUser, Content: "Is the sky blue?"}},
+		Logprobs:    true,
+		TopLogprobs: Ptr(2),
+	})
+	require.NoError(t, err)
+
+	logprobs := resp.Choices[0].Logprobs
+	require.NotNil(t, logprobs)
+	require.Len(t, logprobs.Content, 2)
+	assert.Equal(t, "yes", logprobs.Content[0].Token)
+	assert.Equal(t, []int{121, 101, 115}, logprobs.Content[0].Bytes)
+	assert.InDelta(t, math.Exp(-0.1), logprobs.Content[0].Probability(), 1e-9)
+
+	assert.InDelta(t, -0.3, logprobs.SequenceLogprob(), 1e-9)
+	assert.InDelta(t, math.Exp(-0.3), logprobs.SequenceProbability(), 1e-9)
+
+	alternatives := logprobs.TopAlternatives(1)
+	require.Len(t, alternatives, 2)
+	assert.Equal(t, []TopLogprob{{Token: "no", Logprob: -2.5, Bytes: []int{110, 111}}}, alternatives[0])
+	assert.Empty(t, alternatives[1])
+	assert.Len(t, logprobs.TopAlternatives(-1)[0], 2)
+
+	var none *Logprobs
+	assert.Zero(t, none.SequenceLogprob())
+	assert.Equal(t, 1.0, none.SequenceProbability())
+	assert.Nil(t, none.TopAlternatives(1))
+}
@@ -1,5 +1,11 @@
 package groq
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
 /*
 For more information, see the Groq documentation: https://console.groq.com/docs/api-reference
 */
@@ -10,63 +16,147 @@ const (
 	MessageRoleSystem    MessageRole = "system"
 	MessageRoleUser      MessageRole = "user"
 	MessageRoleAssistant MessageRole = "assistant"
+	MessageRoleTool      MessageRole = "tool"
 )
 
-// Message represents a message in the chat completion request.
+// Message represents a message of any role, in the chat completion request
+// and response.
+//
+// The content of a message is either the text in Content, or, for user
+// messages that mix text and images, the parts in ContentParts.
 type Message struct {
-	Role    MessageRole `json:"role"`    // Role of the message sender (e.g., "user" or "assistant")
-	Content string      `json:"content"` // Content of the message
+	Role         MessageRole   `json:"role"`                   // Role of the message sender (e.g., "user" or "assistant")
+	Content      string        `json:"content"`                // Content of the message. Assistant messages with tool calls may leave it empty.
+	ContentParts []ContentPart `json:"-"`                      // Content of a multimodal user message, sent instead of Content.
+	Name         string        `json:"name,omitempty"`         // An optional name for the participant. Provides the model information to differentiate between participants of the same role.
+	ToolCalls    []ToolCall    `json:"tool_calls,omitempty"`   // The tool calls generated by the model, in assistant messages.
+	ToolCallID   string        `json:"tool_call_id,omitempty"` // Tool call that this message is responding to, in tool messages.
+}
+
+// messageJSON is the wire form of Message, whose content is a string or an
+// array of parts.
+type messageJSON struct {
+	Role       MessageRole     `json:"role"`
+	Content    json.RawMessage `json:"content"`
+	Name       string          `json:"name,omitempty"`
+	ToolCalls  []ToolCall      `json:"tool_calls,omitempty"`
+	ToolCallID string          `json:"tool_call_id,omitempty"`
 }
 
-// TODO(@Kcrong): Handle SystemMessage, UserMessage, AssistantMessage, ToolMessage in the completion response.
+func (m Message) MarshalJSON() ([]byte, error) {
+	if m.Content != "" && len(m.ContentParts) > 0 {
+		return nil, errors.New("message has both Content and ContentParts")
+	}
 
-type SystemMessage struct {
-	Content    *string `json:"content,omitempty"`      // The contents of the system message.
-	Name       *string `json:"name,omitempty"`         // An optional name for the participant. Provides the model information to differentiate between participants of the same role.
-	Role       *string `json:"role,omitempty"`         // The role of the messages author, in this case system.
-	ToolCallID *string `json:"tool_call_id,omitempty"` // DO NOT USE. This field is present because OpenAI allows it and users send it.
+	var (
+		content []byte
+		err     error
+	)
+	if len(m.ContentParts) > 0 {
+		content, err = json.Marshal(m.ContentParts)
+	} else {
+		content, err = json.Marshal(m.Content)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(messageJSON{
+		Role:       m.Role,
+		Content:    content,
+		Name:       m.Name,
+		ToolCalls:  m.ToolCalls,
+		ToolCallID: m.ToolCallID,
+	})
 }
 
-type ImageURL struct {
-	Detail *string `json:"type,omitempty"` // Specifies the detail level of the image.
-	URL    *string `json:"url,omitempty"`  // Either a URL of the image or the base64 encoded image data.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var msg messageJSON
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	*m = Message{
+		Role:       msg.Role,
+		Name:       msg.Name,
+		ToolCalls:  msg.ToolCalls,
+		ToolCallID: msg.ToolCallID,
+	}
+
+	switch {
+	case len(msg.Content) == 0 || string(msg.Content) == "null":
+	case msg.Content[0] == '[':
+		if err := json.Unmarshal(msg.Content, &m.ContentParts); err != nil {
+			return fmt.Errorf("failed to unmarshal content parts: %w", err)
+		}
+	default:
+		if err := json.Unmarshal(msg.Content, &m.Content); err != nil {
+			return fmt.Errorf("content must be a string or an array of parts: %w", err)
+		}
+	}
+
+	return nil
 }
 
-type ContentPart struct {
-	ImageURL *ImageURL `json:"image_url,omitempty"` // The image URL content part.
-	Text     *string   `json:"text,omitempty"`      // The text content.
-	Type     *string   `json:"type,omitempty"`      // The type of the content part.
+// NewToolMessage returns the tool message answering the tool call with the
+// given ID.
+func NewToolMessage(toolCallID, content string) Message {
+	return Message{
+		Role:       MessageRoleTool,
+		Content:    content,
+		ToolCallID: toolCallID,
+	}
+}
+
+type ImageDetail string
+
+const (
+	ImageDetailAuto ImageDetail = "auto"
+	ImageDetailLow  ImageDetail = "low"
+	ImageDetailHigh ImageDetail = "high"
+)
+
+type ImageURL struct {
+	URL    string      `json:"url"`              // Either a URL of the image or the base64 encoded image data as a data URL.
+	Detail ImageDetail `json:"detail,omitempty"` // Specifies the detail level of the image.
 }
 
-type UserMessage[ContentType string | []ContentPart] struct {
-	Content    *ContentType `json:"content,omitempty"`      // The contents of the user message.
-	Name       *string      `json:"name,omitempty"`         // An optional name for the participant. Provides the model information to differentiate between participants of the same role.
-	Role       *string      `json:"role,omitempty"`         // The role of the message's author, in this case user.
-	ToolCallID *string      `json:"tool_call_id,omitempty"` // DO NOT USE. This field is present because OpenAI allows it and users send it.
+type ContentPartType string
+
+const (
+	ContentPartTypeText     ContentPartType = "text"
+	ContentPartTypeImageURL ContentPartType = "image_url"
+)
+
+// ContentPart is a part of the content of a multimodal user message.
+type ContentPart struct {
+	Type     ContentPartType `json:"type"`                // The type of the content part.
+	Text     string          `json:"text,omitempty"`      // The text content.
+	ImageURL *ImageURL       `json:"image_url,omitempty"` // The image URL content part.
 }
 
-type ToolCallFunction struct {
-	Arguments *string `json:"arguments,omitempty"` // The arguments to call the function with, as generated by the model in JSON format. Note that the model does not always generate valid JSON, and may hallucinate parameters not defined by your function schema. Validate the arguments in your code before calling your function.
-	Name      *string `json:"name,omitempty"`      // The name of the function to call.
+// NewTextPart returns a text ContentPart.
+func NewTextPart(text string) ContentPart {
+	return ContentPart{Type: ContentPartTypeText, Text: text}
 }
 
-type ToolCall struct {
-	Function *ToolCallFunction `json:"function,omitempty"` // The function call that the model called.
-	ID       *string           `json:"id,omitempty"`       // The ID of the tool call.
-	Type     *string           `json:"type,omitempty"`     // The type of the tool. Currently, only function is supported.
+// NewImageURLPart returns an image ContentPart referring to url, which is
+// either a URL of the image or a data URL. See NewImagePartFromBytes to
+// embed local images.
+func NewImageURLPart(url string) ContentPart {
+	return ContentPart{Type: ContentPartTypeImageURL, ImageURL: &ImageURL{URL: url}}
 }
 
-type AssistantMessage struct {
-	Content    *string     `json:"content,omitempty"`      // The contents of the assistant message. Required unless tool_calls or function_call is specified.
-	Name       *string     `json:"name,omitempty"`         // An optional name for the participant. Provides the model information to differentiate between participants of the same role.
-	Role       *string     `json:"role,omitempty"`         // The role of the message's author, in this case assistant.
-	ToolCallID *string     `json:"tool_call_id,omitempty"` // DO NOT USE. This field is present because OpenAI allows it and users send it.
-	ToolCalls  *[]ToolCall `json:"tool_calls,omitempty"`   // The tool calls generated by the model, such as function calls.
+// ToolCallFunction is the function call of a ToolCall.
+type ToolCallFunction struct {
+	Name      string `json:"name,omitempty"` // The name of the function to call.
+	Arguments string `json:"arguments"`      // The arguments to call the function with, as generated by the model in JSON format. Note that the model does not always generate valid JSON, and may hallucinate parameters not defined by your function schema. Validate the arguments in your code before calling your function.
 }
 
-type ToolMessage struct {
-	Content    *string `json:"content,omitempty"`      // The contents of the tool message.
-	Name       *string `json:"name,omitempty"`         // DO NOT USE. This field is present because OpenAI allows it and users send it.
-	Role       *string `json:"role,omitempty"`         // The role of the message's author, in this case tool.
-	ToolCallID *string `json:"tool_call_id,omitempty"` // Tool call that this message is responding to.
+// ToolCall is a call of a tool generated by the model.
+type ToolCall struct {
+	Index    int              `json:"index,omitempty"` // Position of the tool call in the message, set in streamed deltas to join the fragments of each call.
+	ID       string           `json:"id,omitempty"`    // The ID of the tool call.
+	Type     ToolType         `json:"type,omitempty"`  // The type of the tool. Currently, only function is supported.
+	Function ToolCallFunction `json:"function"`        // The function call that the model called.
 }
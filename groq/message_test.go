@@ -0,0 +1,98 @@
+package groq
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageToolCallsRoundTrip(t *testing.T) {
+	const body = `{
+		"id": "chatcmpl-1",
+		"choices": [{
+			"index": 0,
+			"message": {
+				"role": "assistant",
+				"tool_calls": [{
+					"id": "call_d5wg",
+					"type": "function",
+					"function": {"name": "get_weather", "arguments": "{\"location\":\"Seoul\"}"}
+				}]
+			},
+			"finish_reason": "tool_calls"
+		}]
+	}`
+
+	var resp ChatCompletionResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+
+	assistant := resp.Choices[0].Message
+	require.Len(t, assistant.ToolCalls, 1)
+	assert.Equal(t, ToolCall{
+		ID:   "call_d5wg",
+		Type: ToolTypeFunction,
+		Function: ToolCallFunction{
+			Name:      "get_weather",
+			Arguments: `{"location":"Seoul"}`,
+		},
+	}, assistant.ToolCalls[0])
+
+	data, err := json.Marshal([]Message{assistant, NewToolMessage("call_d5wg", `{"celsius":21}`)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{
+			"role": "assistant",
+			"content": "",
+			"tool_calls": [{
+				"id": "call_d5wg",
+				"type": "function",
+				"function": {"name": "get_weather", "arguments": "{\"location\":\"Seoul\"}"}
+			}]
+		},
+		{"role": "tool", "content": "{\"celsius\":21}", "tool_call_id": "call_d5wg"}
+	]`, string(data))
+}
+
+func TestMessageContentParts(t *testing.T) {
+	// A 1x1 transparent PNG.
+	png := []byte{
+		0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d, 0x49, 0x48, 0x44, 0x52,
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x08, 0x06, 0x00, 0x00, 0x00, 0x1f, 0x15, 0xc4,
+		0x89, 0x00, 0x00, 0x00, 0x0d, 0x49, 0x44, 0x41, 0x54, 0x78, 0x9c, 0x63, 0x00, 0x01, 0x00, 0x00,
+		0x05, 0x00, 0x01, 0x0d, 0x0a, 0x2d, 0xb4, 0x00, 0x00, 0x00, 0x00, 0x49, 0x45, 0x4e, 0x44, 0xae,
+		0x42, 0x60, 0x82,
+	}
+
+	image, err := NewImagePartFromReader(bytes.NewReader(png))
+	require.NoError(t, err)
+	image.ImageURL.Detail = ImageDetailLow
+
+	msg := Message{
+		Role:         MessageRoleUser,
+		ContentParts: []ContentPart{NewTextPart("What's in this image?"), image},
+	}
+
+	data, err := json.Marshal(msg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"role": "user",
+		"content": [
+			{"type": "text", "text": "What's in this image?"},
+			{"type": "image_url", "image_url": {"url": "data:image/png;base64,`+base64.StdEncoding.EncodeToString(png)+`", "detail": "low"}}
+		]
+	}`, string(data))
+
+	var decoded Message
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, msg, decoded)
+
+	_, err = NewImagePartFromBytes([]byte("plain text"))
+	assert.ErrorIs(t, err, ErrUnsupportedImageType)
+
+	_, err = NewImagePartFromReader(bytes.NewReader(make([]byte, MaxImageSize)))
+	assert.ErrorIs(t, err, ErrImageTooLarge)
+}
@@ -0,0 +1,70 @@
+package groq
+
+import (
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+	"time"
+)
+
+// ResponseMeta describes the HTTP response an API result was decoded from.
+type ResponseMeta struct {
+	StatusCode int         // HTTP status code of the response
+	Header     http.Header // Response headers
+	RequestID  string      // Value of the x-request-id header, to quote to Groq support
+	KeyID      string      // Redacted form of the API key that served the request
+
+	// Latency is the time from sending the request until the response
+	// body was read. For streams, it's the time until the stream opened.
+	Latency time.Duration
+	// TimeToFirstByte is the time from sending the request until the first
+	// byte of the response arrived.
+	TimeToFirstByte time.Duration
+
+	// Body is the raw response body. It's only kept by clients created
+	// with WithRawResponseBody, and never for streams.
+	Body []byte
+}
+
+// requestAttempt records the API key and timing of a single request attempt.
+type requestAttempt struct {
+	key       string
+	start     time.Time
+	firstByte time.Time
+	wrote     atomic.Bool // Whether the whole request was written
+}
+
+// trackAttempt returns a copy of httpReq whose timing is recorded by the
+// returned requestAttempt.
+func trackAttempt(httpReq *http.Request, key string) (*http.Request, *requestAttempt) {
+	a := &requestAttempt{key: key}
+	trace := &httptrace.ClientTrace{
+		GotFirstResponseByte: func() {
+			a.firstByte = time.Now()
+		},
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			a.wrote.Store(info.Err == nil)
+		},
+	}
+
+	httpReq = httpReq.WithContext(httptrace.WithClientTrace(httpReq.Context(), trace))
+	a.start = time.Now()
+
+	return httpReq, a
+}
+
+// meta describes resp, with the latency measured until now.
+func (a *requestAttempt) meta(resp *http.Response) *ResponseMeta {
+	meta := &ResponseMeta{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		RequestID:  resp.Header.Get("x-request-id"),
+		KeyID:      redactKey(a.key),
+		Latency:    time.Since(a.start),
+	}
+	if !a.firstByte.IsZero() {
+		meta.TimeToFirstByte = a.firstByte.Sub(a.start)
+	}
+
+	return meta
+}
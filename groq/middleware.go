@@ -0,0 +1,67 @@
+package groq
+
+import (
+	"context"
+)
+
+// Handler sends a chat completion request and returns its response.
+// The client's own Handler validates, paces and sends the request.
+type Handler func(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error)
+
+// Middleware wraps a Handler, e.g. to log, measure, rewrite or reject
+// requests before they are sent and to inspect the response or error.
+//
+// For example, a middleware that injects a default system prompt:
+//
+//	func(next groq.Handler) groq.Handler {
+//		return func(ctx context.Context, req groq.ChatCompletionRequest) (*groq.ChatCompletionResponse, error) {
+//			req.Messages = append([]groq.Message{{Role: groq.MessageRoleSystem, Content: prompt}}, req.Messages...)
+//			return next(ctx, req)
+//		}
+//	}
+type Middleware func(next Handler) Handler
+
+// StreamReader yields the chunks of a streamed chat completion.
+type StreamReader interface {
+	// Recv returns the next chunk, or io.EOF once the stream has ended.
+	Recv() (*ChatCompletionResponse, error)
+	// Close stops the stream and releases its connection. It's safe to call
+	// more than once.
+	Close() error
+}
+
+// StreamMetaReader is a StreamReader that knows the HTTP response of the
+// stream before its first chunk, as the client's own does. A StreamReader
+// wrapping another should forward Meta to it, so the stream reports its
+// response even if it fails before its first chunk.
+type StreamMetaReader interface {
+	StreamReader
+	// Meta returns the HTTP response the stream is read from.
+	Meta() *ResponseMeta
+}
+
+// streamMeta returns the response of the stream read by reader, or nil if
+// reader doesn't tell.
+func streamMeta(reader StreamReader) *ResponseMeta {
+	if r, ok := reader.(StreamMetaReader); ok {
+		return r.Meta()
+	}
+
+	return nil
+}
+
+// StreamHandler opens a streamed chat completion.
+type StreamHandler func(ctx context.Context, req ChatCompletionRequest) (StreamReader, error)
+
+// StreamMiddleware wraps a StreamHandler. To observe each chunk and the end
+// of the stream, it returns a StreamReader wrapping the one opened by next.
+type StreamMiddleware func(next StreamHandler) StreamHandler
+
+// chain wraps h in middleware, the first one being the outermost.
+func chain[H any, M ~func(H) H](h H, middleware []M) H {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+
+	return h
+}
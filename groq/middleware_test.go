@@ -0,0 +1,98 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatCompletionRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if !assert.Len(t, req.Messages, 2) {
+			return
+		}
+		assert.Equal(t, MessageRoleSystem, req.Messages[0].Role)
+
+		_ = json.NewEncoder(w).Encode(ChatCompletionResponse{ID: "chatcmpl-1"})
+	}))
+	defer server.Close()
+
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error) {
+				calls = append(calls, name)
+				return next(ctx, req)
+			}
+		}
+	}
+	systemPrompt := func(next Handler) Handler {
+		return func(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error) {
+			req.Messages = append([]Message{{Role: MessageRoleSystem, Content: "Be brief."}}, req.Messages...)
+			return next(ctx, req)
+		}
+	}
+
+	c := NewClient("test-key", WithBaseURL(server.URL), WithMiddleware(record("outer"), record("inner"), systemPrompt))
+
+	resp, err := c.CreateChatCompletion(context.Background(), ChatCompletionRequest{
+		Messages: []Message{{Role: MessageRoleUser, Content: "hi"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "chatcmpl-1", resp.ID)
+	assert.Equal(t, []string{"outer", "inner"}, calls)
+}
+
+// countingReader counts the chunks of the StreamReader it wraps.
+type countingReader struct {
+	StreamReader
+	chunks int
+	ended  bool
+}
+
+func (r *countingReader) Recv() (*ChatCompletionResponse, error) {
+	chunk, err := r.StreamReader.Recv()
+	if errors.Is(err, io.EOF) {
+		r.ended = true
+	} else if err == nil {
+		r.chunks++
+	}
+
+	return chunk, err
+}
+
+func TestStreamMiddleware(t *testing.T) {
+	server := newEventStreamServer(t, contentEvent("0"), contentEvent("1"), contentEvent("2"), "data: [DONE]")
+
+	counter := &countingReader{}
+	stream := openStream(t, server, ChatCompletionRequest{}, WithStreamMiddleware(func(next StreamHandler) StreamHandler {
+		return func(ctx context.Context, req ChatCompletionRequest) (StreamReader, error) {
+			reader, err := next(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			counter.StreamReader = reader
+
+			return counter, nil
+		}
+	}))
+
+	var content string
+	for chunk, err := range stream.All() {
+		require.NoError(t, err)
+		content += chunk.Choices[0].Delta.Content
+	}
+
+	assert.Equal(t, "012", content)
+	assert.Equal(t, 3, counter.chunks)
+	assert.True(t, counter.ended, "middleware should observe the end of the stream")
+}
@@ -1,9 +1,7 @@
 package groq
 
 import (
-	"encoding/json"
-	"fmt"
-	"io"
+	"context"
 	"net/http"
 )
 
@@ -16,10 +14,21 @@ const (
 	ModelIDGEMMA     ModelID = "gemma-7b-it"
 )
 
+// modelMaxTokens is the largest MaxTokens each known model accepts.
+var modelMaxTokens = map[ModelID]int{
+	ModelIDLLAMA38B:  8192,
+	ModelIDLLAMA370B: 8192,
+	ModelIDMIXTRAL:   32768,
+	ModelIDGEMMA:     8192,
+}
+
 // ListModelsResponse represents the response from the list models API.
 type ListModelsResponse struct {
 	ObjectType string  `json:"object"` // Type of the object (e.g., "list")
 	Data       []Model `json:"data"`   // List of models
+
+	RateLimit *RateLimitInfo `json:"-"` // Rate limit state reported with the response
+	Meta      *ResponseMeta  `json:"-"` // HTTP response the list was decoded from
 }
 
 // Model represents a single model returned by the list models or retrieve model API.
@@ -30,60 +39,49 @@ type Model struct {
 	OwnedBy       string  `json:"owned_by"`       // Owner of the model
 	Active        bool    `json:"active"`         // Whether the model is active
 	ContextWindow int     `json:"context_window"` // Context window size of the model
+
+	RateLimit *RateLimitInfo `json:"-"` // Rate limit state reported with the response; nil for models in a list
+	Meta      *ResponseMeta  `json:"-"` // HTTP response the model was decoded from; nil for models in a list
 }
 
 // ListModels sends a request to list all available models.
-func (c *client) ListModels() (*ListModelsResponse, error) {
-	url := fmt.Sprintf("%s/v1/models", c.baseURL)
-
-	httpReq, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
+func (c *client) ListModels(ctx context.Context) (*ListModelsResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
-
-	resp, err := c.client.Do(httpReq)
+	httpReq, err := c.newRequest(ctx, http.MethodGet, "/v1/models", nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
+		return nil, err
 	}
-	defer func(Body io.ReadCloser) {
-		_ = Body.Close()
-	}(resp.Body)
 
 	var modelsResp ListModelsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&modelsResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %v", err)
+	meta, err := c.do(httpReq, &modelsResp)
+	if err != nil {
+		return nil, err
 	}
+	modelsResp.RateLimit = ParseRateLimitInfo(meta.Header)
+	modelsResp.Meta = meta
 
 	return &modelsResp, nil
 }
 
 // RetrieveModel sends a request to retrieve a specific model by its ID.
-func (c *client) RetrieveModel(t ModelID) (*Model, error) {
-	url := fmt.Sprintf("%s/v1/models/%s", c.baseURL, t)
+func (c *client) RetrieveModel(ctx context.Context, t ModelID) (*Model, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 
-	httpReq, err := http.NewRequest(http.MethodGet, url, nil)
+	httpReq, err := c.newRequest(ctx, http.MethodGet, "/v1/models/"+string(t), nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		return nil, err
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
-
-	resp, err := c.client.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
-	}
-	defer func(Body io.ReadCloser) {
-		_ = Body.Close()
-	}(resp.Body)
-
 	var modelResp Model
-	if err := json.NewDecoder(resp.Body).Decode(&modelResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %v", err)
+	meta, err := c.do(httpReq, &modelResp)
+	if err != nil {
+		return nil, err
 	}
+	modelResp.RateLimit = ParseRateLimitInfo(meta.Header)
+	modelResp.Meta = meta
 
 	return &modelResp, nil
 }
@@ -0,0 +1,131 @@
+package groq
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures a Client created by NewClient.
+type Option func(*client)
+
+// WithBaseURL points the client at a different API endpoint, such as a
+// gateway, a proxy or a local OpenAI-compatible server.
+// The URL is the prefix of the "/v1/..." paths, e.g. "https://api.groq.com/openai".
+func WithBaseURL(baseURL string) Option {
+	return func(c *client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *client) {
+		if httpClient != nil {
+			c.client = httpClient
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithDefaultHeaders adds headers to every request.
+// The Authorization and Content-Type headers are always set by the client.
+func WithDefaultHeaders(headers http.Header) Option {
+	return func(c *client) {
+		for key, values := range headers {
+			for _, value := range values {
+				c.headers.Add(key, value)
+			}
+		}
+	}
+}
+
+// WithDefaultModel sets the model used by chat completion requests that
+// don't specify one.
+func WithDefaultModel(model ModelID) Option {
+	return func(c *client) {
+		c.defaultModel = model
+	}
+}
+
+// WithTimeout bounds the duration of every non-streaming request.
+// Unlike http.Client.Timeout it doesn't cut off streaming completions, which
+// are bounded by WithFirstTokenTimeout and WithIdleTimeout instead.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *client) {
+		c.timeout = timeout
+	}
+}
+
+// WithFirstTokenTimeout aborts streams whose first token takes longer than
+// timeout to arrive, with a *StreamTimeoutError.
+func WithFirstTokenTimeout(timeout time.Duration) Option {
+	return func(c *client) {
+		c.firstTokenTimeout = timeout
+	}
+}
+
+// WithIdleTimeout aborts streams whose next chunk takes longer than timeout
+// to arrive, with a *StreamTimeoutError.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(c *client) {
+		c.idleTimeout = timeout
+	}
+}
+
+// WithRetryPolicy makes the client retry transient failures according to policy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithLimiter makes the client wait for capacity in limiter before sending
+// chat completion requests, and reconcile it with the reported usage.
+func WithLimiter(limiter *Limiter) Option {
+	return func(c *client) {
+		c.limiter = limiter
+	}
+}
+
+// WithMiddleware wraps every CreateChatCompletion call in middleware.
+// The first middleware is the outermost one. Only chat completions are
+// wrapped: ListModels and RetrieveModel aren't, and streams are wrapped by
+// WithStreamMiddleware instead.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// WithStreamMiddleware wraps every CreateChatCompletionStream call in
+// middleware. The first middleware is the outermost one.
+func WithStreamMiddleware(middleware ...StreamMiddleware) Option {
+	return func(c *client) {
+		c.streamMiddleware = append(c.streamMiddleware, middleware...)
+	}
+}
+
+// WithRawResponseBody keeps the raw body of every non-streaming response in
+// its ResponseMeta, e.g. for debugging or auditing.
+func WithRawResponseBody() Option {
+	return func(c *client) {
+		c.rawResponseBody = true
+	}
+}
+
+// WithKeyProvider makes the client take the API key of each request from
+// provider, such as a KeyPool, instead of the key given to NewClient.
+func WithKeyProvider(provider KeyProvider) Option {
+	return func(c *client) {
+		if provider != nil {
+			c.keys = provider
+		}
+	}
+}
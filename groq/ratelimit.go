@@ -0,0 +1,61 @@
+package groq
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitInfo is the rate limit state reported by the x-ratelimit-*
+// headers of a response. The request limits apply per day and the token
+// limits per minute.
+type RateLimitInfo struct {
+	LimitRequests     int           // Maximum number of requests allowed in the window
+	RemainingRequests int           // Number of requests left in the window
+	ResetRequests     time.Duration // Time until the request limit resets
+	LimitTokens       int           // Maximum number of tokens allowed in the window
+	RemainingTokens   int           // Number of tokens left in the window
+	ResetTokens       time.Duration // Time until the token limit resets
+}
+
+// ParseRateLimitInfo parses the x-ratelimit-* headers of a response.
+// Reset durations use Go's duration format, e.g. "2m59.56s".
+// It returns nil if none of the headers are present; malformed values are
+// left zero.
+func ParseRateLimitInfo(header http.Header) *RateLimitInfo {
+	found := false
+	parseInt := func(key string) int {
+		v := header.Get(key)
+		if v == "" {
+			return 0
+		}
+		found = true
+		n, _ := strconv.Atoi(v)
+
+		return n
+	}
+	parseDuration := func(key string) time.Duration {
+		v := header.Get(key)
+		if v == "" {
+			return 0
+		}
+		found = true
+		d, _ := time.ParseDuration(v)
+
+		return d
+	}
+
+	info := &RateLimitInfo{
+		LimitRequests:     parseInt("x-ratelimit-limit-requests"),
+		RemainingRequests: parseInt("x-ratelimit-remaining-requests"),
+		ResetRequests:     parseDuration("x-ratelimit-reset-requests"),
+		LimitTokens:       parseInt("x-ratelimit-limit-tokens"),
+		RemainingTokens:   parseInt("x-ratelimit-remaining-tokens"),
+		ResetTokens:       parseDuration("x-ratelimit-reset-tokens"),
+	}
+	if !found {
+		return nil
+	}
+
+	return info
+}
@@ -0,0 +1,30 @@
+package groq
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRateLimitInfo(t *testing.T) {
+	header := http.Header{}
+	header.Set("x-ratelimit-limit-requests", "14400")
+	header.Set("x-ratelimit-remaining-requests", "14370")
+	header.Set("x-ratelimit-reset-requests", "2m59.56s")
+	header.Set("x-ratelimit-limit-tokens", "18000")
+	header.Set("x-ratelimit-remaining-tokens", "17997")
+	header.Set("x-ratelimit-reset-tokens", "7.66s")
+
+	assert.Equal(t, &RateLimitInfo{
+		LimitRequests:     14400,
+		RemainingRequests: 14370,
+		ResetRequests:     2*time.Minute + 59560*time.Millisecond,
+		LimitTokens:       18000,
+		RemainingTokens:   17997,
+		ResetTokens:       7660 * time.Millisecond,
+	}, ParseRateLimitInfo(header))
+
+	assert.Nil(t, ParseRateLimitInfo(http.Header{}))
+}
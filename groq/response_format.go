@@ -0,0 +1,127 @@
+package groq
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ResponseFormat is the format of the model's response: one of
+// ResponseFormatText, ResponseFormatJSONObject and ResponseFormatJSONSchema.
+type ResponseFormat interface {
+	responseFormatType() string
+}
+
+var (
+	_ ResponseFormat = ResponseFormatText{}
+	_ ResponseFormat = ResponseFormatJSONObject{}
+	_ ResponseFormat = ResponseFormatJSONSchema{}
+)
+
+// ResponseFormatText makes the model respond with plain text, the default.
+type ResponseFormatText struct{}
+
+// ResponseFormatJSONObject enables JSON mode, which makes the model respond
+// with a valid JSON object. The messages must instruct the model to produce
+// JSON, so they must mention "JSON".
+type ResponseFormatJSONObject struct{}
+
+// ResponseFormatJSONSchema makes the model respond with JSON matching Schema.
+type ResponseFormatJSONSchema struct {
+	Name        string // The name of the response format. Must be a-z, A-Z, 0-9, or contain underscores and dashes, with a maximum length of 64.
+	Description string // A description of what the response format is for, used by the model to determine how to respond in the format.
+	Schema      any    // The schema for the response format, described as a JSON Schema object.
+	Strict      bool   // Whether to enable strict schema adherence when generating the output.
+}
+
+func (ResponseFormatText) responseFormatType() string       { return "text" }
+func (ResponseFormatJSONObject) responseFormatType() string { return "json_object" }
+func (ResponseFormatJSONSchema) responseFormatType() string { return "json_schema" }
+
+type responseFormatJSON struct {
+	Type       string      `json:"type"`
+	JSONSchema *jsonSchema `json:"json_schema,omitempty"`
+}
+
+type jsonSchema struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      any    `json:"schema,omitempty"`
+	Strict      bool   `json:"strict,omitempty"`
+}
+
+func (f ResponseFormatText) MarshalJSON() ([]byte, error) {
+	return json.Marshal(responseFormatJSON{Type: f.responseFormatType()})
+}
+
+func (f ResponseFormatJSONObject) MarshalJSON() ([]byte, error) {
+	return json.Marshal(responseFormatJSON{Type: f.responseFormatType()})
+}
+
+func (f ResponseFormatJSONSchema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(responseFormatJSON{
+		Type: f.responseFormatType(),
+		JSONSchema: &jsonSchema{
+			Name:        f.Name,
+			Description: f.Description,
+			Schema:      f.Schema,
+			Strict:      f.Strict,
+		},
+	})
+}
+
+// unmarshalResponseFormat decodes the response_format of a request.
+func unmarshalResponseFormat(data []byte) (ResponseFormat, error) {
+	var format responseFormatJSON
+	if err := json.Unmarshal(data, &format); err != nil {
+		return nil, err
+	}
+
+	switch format.Type {
+	case "text":
+		return ResponseFormatText{}, nil
+	case "json_object":
+		return ResponseFormatJSONObject{}, nil
+	case "json_schema":
+		if format.JSONSchema == nil {
+			return nil, errors.New("response_format of type json_schema needs a json_schema")
+		}
+
+		return ResponseFormatJSONSchema{
+			Name:        format.JSONSchema.Name,
+			Description: format.JSONSchema.Description,
+			Schema:      format.JSONSchema.Schema,
+			Strict:      format.JSONSchema.Strict,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown response_format type %q", format.Type)
+	}
+}
+
+// ErrJSONNotMentioned is the validation error of requests in JSON mode whose
+// messages don't mention JSON, which the API rejects.
+var ErrJSONNotMentioned = errors.New("JSON mode requires the messages to mention JSON")
+
+// validateResponseFormat checks that the messages of a request in JSON mode
+// ask for JSON.
+func validateResponseFormat(req ChatCompletionRequest) error {
+	switch req.ResponseFormat.(type) {
+	case ResponseFormatJSONObject, *ResponseFormatJSONObject:
+	default:
+		return nil
+	}
+
+	for _, message := range req.Messages {
+		if strings.Contains(strings.ToLower(message.Content), "json") {
+			return nil
+		}
+		for _, part := range message.ContentParts {
+			if strings.Contains(strings.ToLower(part.Text), "json") {
+				return nil
+			}
+		}
+	}
+
+	return ErrJSONNotMentioned
+}
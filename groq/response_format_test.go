@@ -0,0 +1,116 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseFormatJSON(t *testing.T) {
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"name": map[string]any{"type": "string"}},
+	}
+
+	testcases := []struct {
+		name   string
+		format ResponseFormat
+		json   string
+	}{
+		{name: "text", format: ResponseFormatText{}, json: `{"type":"text"}`},
+		{name: "json_object", format: ResponseFormatJSONObject{}, json: `{"type":"json_object"}`},
+		{
+			name:   "json_schema",
+			format: ResponseFormatJSONSchema{Name: "person", Schema: schema, Strict: true},
+			json:   `{"type":"json_schema","json_schema":{"name":"person","schema":{"type":"object","properties":{"name":{"type":"string"}}},"strict":true}}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := ChatCompletionRequest{Model: ModelIDLLAMA370B, ResponseFormat: tc.format}
+
+			data, err := json.Marshal(req)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"messages":null,"model":"llama3-70b-8192","response_format":`+tc.json+`}`, string(data))
+
+			var decoded ChatCompletionRequest
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, req, decoded)
+		})
+	}
+}
+
+func TestResponseFormatUnknownType(t *testing.T) {
+	var req ChatCompletionRequest
+	err := json.Unmarshal([]byte(`{"response_format":{"type":"xml"}}`), &req)
+	assert.EqualError(t, err, `unknown response_format type "xml"`)
+}
+
+func TestJSONModeRequiresJSONPrompt(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"{}"}}]}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("test-key", WithBaseURL(server.URL))
+	req := ChatCompletionRequest{
+		Model:          ModelIDLLAMA370B,
+		Messages:       []Message{{Role: MessageRoleUser, Content: "List three colors."}},
+		ResponseFormat: ResponseFormatJSONObject{},
+	}
+
+	_, err := c.CreateChatCompletion(context.Background(), req)
+	assert.ErrorIs(t, err, ErrJSONNotMentioned)
+	req.ResponseFormat = &ResponseFormatJSONObject{}
+	_, err = c.CreateChatCompletion(context.Background(), req)
+	assert.ErrorIs(t, err, ErrJSONNotMentioned)
+	req.Stream = true
+	_, err = c.CreateChatCompletionStream(context.Background(), req)
+	assert.ErrorIs(t, err, ErrJSONNotMentioned)
+	req.Stream = false
+	assert.Zero(t, calls.Load())
+
+	req.Messages = append(req.Messages, Message{
+		Role:         MessageRoleUser,
+		ContentParts: []ContentPart{NewTextPart("Answer in Json.")},
+	})
+	_, err = c.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func TestJSONValidateError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"message":"Failed to generate JSON. Please adjust your prompt.","type":"invalid_request_error","code":"json_validate_failed","failed_generation":"{\"name\": "}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("test-key", WithBaseURL(server.URL))
+	_, err := c.CreateChatCompletion(context.Background(), ChatCompletionRequest{
+		Model:          ModelIDLLAMA370B,
+		Messages:       []Message{{Role: MessageRoleUser, Content: "Reply with a JSON object."}},
+		ResponseFormat: ResponseFormatJSONObject{},
+	})
+	require.Error(t, err)
+	assert.True(t, IsJSONValidateFailed(err))
+
+	var validateErr *JSONValidateError
+	require.True(t, errors.As(err, &validateErr), "error should be a *JSONValidateError")
+	assert.Equal(t, `{"name": `, validateErr.FailedGeneration)
+	assert.Equal(t, http.StatusBadRequest, validateErr.StatusCode)
+
+	apiErr, ok := asAPIError(err)
+	require.True(t, ok, "error should wrap an *APIError")
+	assert.Equal(t, ErrorCodeJSONValidateFailed, apiErr.Code)
+}
@@ -0,0 +1,214 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+	defaultMultiplier     = 2
+	defaultMaxRetryAfter  = time.Minute
+)
+
+var defaultRetryableStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy configures how the client retries failed requests.
+// The zero value disables retries.
+//
+// Between attempts the client waits for the delay requested by the server
+// through the Retry-After or x-ratelimit-reset-* headers, or otherwise for an
+// exponential backoff with jitter. A request whose sending failed is only
+// retried if it wasn't written yet, unless it's a GET, so the API never
+// receives a chat completion twice. With a KeyProvider other than the
+// client's single key, a 429 is retried after the backoff rather than the
+// rate limited key's reset. Streaming requests are only retried until
+// their response is accepted.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts per request, including
+	// the first one. Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. Defaults to 500ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the exponential backoff. Defaults to 30s.
+	MaxBackoff time.Duration
+	// MaxRetryAfter caps the delays requested by the server, e.g. by the
+	// reset of a daily limit. Defaults to one minute.
+	MaxRetryAfter time.Duration
+	// Multiplier is the growth factor of the backoff between attempts.
+	// Defaults to 2.
+	Multiplier float64
+	// Jitter randomizes each backoff by up to ±Jitter of its value.
+	// Must be in [0, 1); zero disables randomization.
+	Jitter float64
+	// RetryableStatuses lists the HTTP status codes that are retried.
+	// Defaults to 429, 500, 502, 503 and 504.
+	RetryableStatuses []int
+}
+
+// DefaultRetryPolicy returns a policy that makes up to three attempts with
+// the default backoff and a 20% jitter.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		Jitter:      0.2,
+	}
+}
+
+// canRetry reports whether another attempt may follow the given one.
+func (p RetryPolicy) canRetry(attempt int) bool {
+	return attempt < p.MaxAttempts
+}
+
+// retryableStatus reports whether a response with the status code is retried.
+func (p RetryPolicy) retryableStatus(code int) bool {
+	statuses := p.RetryableStatuses
+	if statuses == nil {
+		statuses = defaultRetryableStatuses
+	}
+
+	return slices.Contains(statuses, code)
+}
+
+// delay returns the wait before the attempt following the given one, using
+// the server's hint from header when there is one.
+func (p RetryPolicy) delay(attempt int, header http.Header) time.Duration {
+	if d, ok := retryAfter(header); ok {
+		maxRetryAfter := p.MaxRetryAfter
+		if maxRetryAfter <= 0 {
+			maxRetryAfter = defaultMaxRetryAfter
+		}
+
+		return min(d, maxRetryAfter)
+	}
+
+	return p.backoff(attempt)
+}
+
+// backoff returns the exponential backoff after the given attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	initial, maxBackoff, multiplier := p.InitialBackoff, p.MaxBackoff, p.Multiplier
+	if initial <= 0 {
+		initial = defaultInitialBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+	if multiplier < 1 {
+		multiplier = defaultMultiplier
+	}
+
+	d := math.Min(float64(initial)*math.Pow(multiplier, float64(attempt-1)), float64(maxBackoff))
+	if p.Jitter > 0 && p.Jitter < 1 {
+		d += d * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(d)
+}
+
+// retryAfter extracts the delay requested by the server from the Retry-After
+// header, or from the reset header of an exhausted rate limit.
+func retryAfter(header http.Header) (time.Duration, bool) {
+	if header == nil {
+		return 0, false
+	}
+
+	if v := header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.ParseFloat(v, 64); err == nil && seconds >= 0 {
+			return time.Duration(seconds * float64(time.Second)), true
+		}
+		if at, err := http.ParseTime(v); err == nil {
+			return max(time.Until(at), 0), true
+		}
+	}
+
+	var (
+		d     time.Duration
+		found bool
+	)
+	for _, limit := range []string{"requests", "tokens"} {
+		if header.Get("x-ratelimit-remaining-"+limit) != "0" {
+			continue
+		}
+		reset, err := time.ParseDuration(header.Get("x-ratelimit-reset-" + limit))
+		if err != nil {
+			continue
+		}
+		d, found = max(d, reset), true
+	}
+
+	return d, found
+}
+
+// isRetryableError reports whether err is a transient transport failure
+// after which a request with method can be sent again. Only GET requests are
+// retried once written, since the API may have processed the others.
+func isRetryableError(err error, method string, wrote bool) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	if wrote && method != http.MethodGet {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// sleep waits for d, returning early with the context's error if it's done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// rewindRequest returns a copy of httpReq with a fresh body, so it can be
+// sent again.
+func rewindRequest(httpReq *http.Request) (*http.Request, error) {
+	retry := httpReq.Clone(httpReq.Context())
+	if httpReq.GetBody == nil {
+		return retry, nil
+	}
+
+	body, err := httpReq.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	retry.Body = body
+
+	return retry, nil
+}
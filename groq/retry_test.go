@@ -0,0 +1,151 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(ListModelsResponse{ObjectType: "list"})
+	}))
+	defer server.Close()
+
+	c := NewClient("test-key", WithBaseURL(server.URL), WithRetryPolicy(DefaultRetryPolicy()))
+
+	models, err := c.ListModels(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "list", models.ObjectType)
+	assert.EqualValues(t, 3, attempts.Load())
+}
+
+func TestRetryPolicyGivesUp(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	c := NewClient("test-key", WithBaseURL(server.URL), WithRetryPolicy(DefaultRetryPolicy()))
+
+	_, err := c.ListModels(context.Background())
+	apiErr, ok := asAPIError(err)
+	require.True(t, ok, "error should be an *APIError")
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.EqualValues(t, 1, attempts.Load(), "non-retryable status must not be retried")
+}
+
+func TestRetryAfter(t *testing.T) {
+	testcases := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+		ok     bool
+	}{
+		{
+			name:   "retry-after seconds",
+			header: http.Header{"Retry-After": []string{"2"}},
+			want:   2 * time.Second,
+			ok:     true,
+		},
+		{
+			name: "exhausted token budget",
+			header: http.Header{
+				"X-Ratelimit-Remaining-Requests": []string{"10"},
+				"X-Ratelimit-Reset-Requests":     []string{"2m59.56s"},
+				"X-Ratelimit-Remaining-Tokens":   []string{"0"},
+				"X-Ratelimit-Reset-Tokens":       []string{"7.66s"},
+			},
+			want: 7660 * time.Millisecond,
+			ok:   true,
+		},
+		{
+			name:   "no hint",
+			header: http.Header{},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := retryAfter(tc.header)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRetryPolicyDelayIsCapped(t *testing.T) {
+	header := http.Header{"Retry-After": []string{"86400"}}
+
+	assert.Equal(t, time.Minute, DefaultRetryPolicy().delay(1, header))
+	assert.Equal(t, 5*time.Second, RetryPolicy{MaxRetryAfter: 5 * time.Second}.delay(1, header))
+}
+
+func TestIsRetryableError(t *testing.T) {
+	testcases := []struct {
+		name   string
+		err    error
+		method string
+		wrote  bool
+		want   bool
+	}{
+		{name: "refused", err: syscall.ECONNREFUSED, method: http.MethodPost, wrote: true, want: true},
+		{name: "reset before writing", err: syscall.ECONNRESET, method: http.MethodPost, want: true},
+		{name: "reset after writing", err: syscall.ECONNRESET, method: http.MethodPost, wrote: true},
+		{name: "EOF after writing a GET", err: io.EOF, method: http.MethodGet, wrote: true, want: true},
+		{name: "canceled", err: context.Canceled, method: http.MethodGet},
+		{name: "other", err: fmt.Errorf("unsupported protocol scheme"), method: http.MethodPost},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, isRetryableError(tc.err, tc.method, tc.wrote))
+		})
+	}
+}
+
+func TestRetryPolicyDoesNotResendCompletions(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		_, _ = io.Copy(io.Discard, r.Body)
+
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if !assert.NoError(t, err) {
+			return
+		}
+		_ = conn.Close()
+	}))
+	defer server.Close()
+
+	c := NewClient("test-key", WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+
+	_, err := c.CreateChatCompletion(context.Background(), ChatCompletionRequest{
+		Messages: []Message{{Role: MessageRoleUser, Content: "hi"}},
+	})
+	require.Error(t, err)
+	assert.EqualValues(t, 1, attempts.Load(), "a completion the server may have processed must not be sent again")
+
+	_, err = c.ListModels(context.Background())
+	require.Error(t, err)
+	assert.EqualValues(t, 4, attempts.Load(), "GET requests are retried")
+}
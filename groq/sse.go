@@ -0,0 +1,124 @@
+package groq
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+)
+
+// maxEventLineSize is the longest line of an event stream the decoder reads.
+const maxEventLineSize = 4 << 20
+
+// sseEvent is an event of a server-sent event stream.
+type sseEvent struct {
+	ID   string // Last event ID of the stream when the event was dispatched
+	Type string // Type of the event, "message" unless set by an event field
+	Data string // Data of the event, its data lines joined by newlines
+}
+
+// sseDecoder reads the events of a server-sent event stream, as specified by
+// https://html.spec.whatwg.org/multipage/server-sent-events.html. It never
+// reconnects: retry fields are ignored.
+type sseDecoder struct {
+	scanner *bufio.Scanner
+	lastID  string
+	started bool
+}
+
+func newSSEDecoder(r io.Reader) *sseDecoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxEventLineSize)
+	scanner.Split(scanEventLines)
+
+	return &sseDecoder{scanner: scanner}
+}
+
+// Next returns the next event of the stream, or io.EOF once the stream has
+// ended. An event that isn't terminated by a blank line is discarded.
+func (d *sseDecoder) Next() (sseEvent, error) {
+	var (
+		eventType string
+		data      strings.Builder
+		hasData   bool
+	)
+
+	for d.scanner.Scan() {
+		line := d.scanner.Text()
+		if !d.started {
+			line = strings.TrimPrefix(line, "\ufeff")
+			d.started = true
+		}
+
+		if line == "" {
+			if !hasData {
+				eventType = ""
+				continue
+			}
+			if eventType == "" {
+				eventType = "message"
+			}
+
+			return sseEvent{ID: d.lastID, Type: eventType, Data: strings.TrimSuffix(data.String(), "\n")}, nil
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				d.lastID = value
+			}
+		}
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		return sseEvent{}, err
+	}
+
+	return sseEvent{}, io.EOF
+}
+
+// scanEventLines is a bufio.SplitFunc splitting lines ended by CRLF, LF or
+// CR, as event streams allow.
+func scanEventLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+		if i+1 < len(data) {
+			if data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+
+			return i + 1, data[:i], nil
+		}
+		if atEOF {
+			return i + 1, data[:i], nil
+		}
+
+		// A CR at the end of the buffer may be followed by a LF.
+		return 0, nil, nil
+	}
+
+	if atEOF {
+		return len(data), data, nil
+	}
+
+	return 0, nil, nil
+}
@@ -0,0 +1,41 @@
+package groq
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEDecoder(t *testing.T) {
+	const stream = "\ufeff: comment\n" +
+		"data: first\n\n" +
+		"event: error\r\ndata:{\"a\":1}\r\nid: 7\r\n\r\n" +
+		"data: multi\rdata:  line\r\r" +
+		"id\n\n" +
+		"retry: 1000\ndata\n\n" +
+		"data: [DONE]\n\n" +
+		"data: incomplete"
+
+	d := newSSEDecoder(strings.NewReader(stream))
+
+	var events []sseEvent
+	for {
+		event, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+
+	assert.Equal(t, []sseEvent{
+		{Type: "message", Data: "first"},
+		{ID: "7", Type: "error", Data: `{"a":1}`},
+		{ID: "7", Type: "message", Data: "multi\n line"},
+		{Type: "message", Data: ""},
+		{Type: "message", Data: "[DONE]"},
+	}, events)
+}
@@ -0,0 +1,217 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// maxStopSequences is the number of stop sequences the API accepts.
+const maxStopSequences = 4
+
+// Stop is the list of sequences, at most four, where the model stops
+// generating, e.g. Stop{"\n\n", "[end]"}. It's sent as a string when it has a
+// single sequence, and as an array otherwise.
+type Stop []string
+
+func (s Stop) MarshalJSON() ([]byte, error) {
+	if len(s) == 1 {
+		return json.Marshal(s[0])
+	}
+
+	return json.Marshal([]string(s))
+}
+
+func (s *Stop) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		var sequence string
+		if err := json.Unmarshal(data, &sequence); err != nil {
+			return err
+		}
+		*s = Stop{sequence}
+
+		return nil
+	}
+
+	var sequences []string
+	if err := json.Unmarshal(data, &sequences); err != nil {
+		return fmt.Errorf("stop must be a string or an array of strings: %w", err)
+	}
+	*s = sequences
+
+	return nil
+}
+
+// EnforceStop returns a StreamMiddleware cutting streams at the request's
+// stop sequences on the client, in case the model emits one anyway. A stop
+// sequence may straddle chunks: text that could start one is held back until
+// the next chunk tells. Once every choice has hit a stop sequence, the chunk
+// ending it has the finish reason "stop" and the rest of the output is
+// dropped; the stream is still read to its end, for the usage and metadata
+// sent with its last chunks.
+func EnforceStop() StreamMiddleware {
+	return func(next StreamHandler) StreamHandler {
+		return func(ctx context.Context, req ChatCompletionRequest) (StreamReader, error) {
+			reader, err := next(ctx, req)
+			if err != nil || len(req.StopSequences) == 0 {
+				return reader, err
+			}
+
+			return &stopReader{
+				StreamReader: reader,
+				stop:         req.StopSequences,
+				pending:      make(map[int]string),
+				stopped:      make(map[int]bool),
+			}, nil
+		}
+	}
+}
+
+// stopReader is the StreamReader of EnforceStop.
+type stopReader struct {
+	StreamReader
+	stop    Stop
+	pending map[int]string // Held back content of each choice
+	stopped map[int]bool   // Whether each choice seen has hit a stop sequence
+	last    *ChatCompletionResponse
+	cutOff  bool // Whether every choice has hit a stop sequence
+	ended   bool
+}
+
+func (r *stopReader) Recv() (*ChatCompletionResponse, error) {
+	if r.ended {
+		return nil, io.EOF
+	}
+
+	for {
+		chunk, err := r.StreamReader.Recv()
+		if errors.Is(err, io.EOF) {
+			r.ended = true
+			if flushed := r.flush(); flushed != nil {
+				return flushed, nil
+			}
+
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if r.cutOff {
+			// Only pass on what comes with the output, without the output.
+			chunk.Choices = nil
+			if chunk.XGroq == nil && chunk.streamUsage() == nil {
+				continue
+			}
+
+			return chunk, nil
+		}
+		r.last = chunk
+
+		for i := range chunk.Choices {
+			r.cut(&chunk.Choices[i])
+		}
+
+		r.cutOff = len(r.stopped) > 0
+		for _, stopped := range r.stopped {
+			r.cutOff = r.cutOff && stopped
+		}
+
+		return chunk, nil
+	}
+}
+
+func (r *stopReader) Meta() *ResponseMeta {
+	return streamMeta(r.StreamReader)
+}
+
+// cut truncates the content of a choice at the first stop sequence, holding
+// back its end if it may be the start of one.
+func (r *stopReader) cut(choice *Choice) {
+	if r.stopped[choice.Index] {
+		choice.Delta.Content = ""
+		return
+	}
+
+	content := r.pending[choice.Index] + choice.Delta.Content
+	delete(r.pending, choice.Index)
+	r.stopped[choice.Index] = false
+
+	if i := r.index(content); i >= 0 {
+		choice.Delta.Content = content[:i]
+		choice.FinishReason = "stop"
+		r.stopped[choice.Index] = true
+
+		return
+	}
+
+	if choice.FinishReason == "" {
+		if n := r.partialSuffix(content); n > 0 {
+			r.pending[choice.Index] = content[len(content)-n:]
+			content = content[:len(content)-n]
+		}
+	}
+	choice.Delta.Content = content
+}
+
+// index returns the index of the first stop sequence in s, or -1.
+func (r *stopReader) index(s string) int {
+	first := -1
+	for _, sequence := range r.stop {
+		if sequence == "" {
+			continue
+		}
+		if i := strings.Index(s, sequence); i >= 0 && (first < 0 || i < first) {
+			first = i
+		}
+	}
+
+	return first
+}
+
+// partialSuffix returns the length of the longest end of s that is the start
+// of a stop sequence.
+func (r *stopReader) partialSuffix(s string) int {
+	var longest int
+	for _, sequence := range r.stop {
+		for n := min(len(sequence)-1, len(s)); n > longest; n-- {
+			if strings.HasSuffix(s, sequence[:n]) {
+				longest = n
+				break
+			}
+		}
+	}
+
+	return longest
+}
+
+// flush returns a chunk with the content still held back when the stream
+// ended, or nil if there is none.
+func (r *stopReader) flush() *ChatCompletionResponse {
+	if len(r.pending) == 0 || r.last == nil {
+		return nil
+	}
+
+	indexes := make([]int, 0, len(r.pending))
+	for index := range r.pending {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	chunk := *r.last
+	chunk.Choices = nil
+	for _, index := range indexes {
+		chunk.Choices = append(chunk.Choices, Choice{
+			Index: index,
+			Delta: Message{Role: MessageRoleAssistant, Content: r.pending[index]},
+		})
+	}
+	r.pending = nil
+
+	return &chunk
+}
@@ -0,0 +1,125 @@
+package groq
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopJSON(t *testing.T) {
+	testcases := []struct {
+		name string
+		stop Stop
+		json string
+	}{
+		{name: "single", stop: Stop{"[end]"}, json: `"[end]"`},
+		{name: "several", stop: Stop{"\n\n", "[end]"}, json: `["\n\n","[end]"]`},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.stop)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.json, string(data))
+
+			var decoded Stop
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, tc.stop, decoded)
+		})
+	}
+
+	data, err := json.Marshal(ChatCompletionRequest{Model: ModelIDLLAMA370B})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "stop")
+
+	assert.Error(t, json.Unmarshal([]byte(`42`), new(Stop)))
+}
+
+func TestValidateStop(t *testing.T) {
+	err := ChatCompletionRequest{
+		Messages:      []Message{{Role: MessageRoleUser, Content: "hi"}},
+		StopSequences: Stop{"a", "", "c", "d", "e"},
+	}.Validate()
+	assert.EqualError(t, err, "groq: invalid request: stop: has 5 sequences, at most 4 are allowed; stop[1]: must not be empty")
+}
+
+func TestEnforceStop(t *testing.T) {
+	testcases := []struct {
+		name    string
+		deltas  []string
+		content []string
+		finish  string
+	}{
+		{name: "within a chunk", deltas: []string{"Hello [end] world", "!"}, content: []string{"Hello "}, finish: "stop"},
+		{name: "across chunks", deltas: []string{"Hello [e", "nd] world", "!"}, content: []string{"Hello ", ""}, finish: "stop"},
+		{name: "false start", deltas: []string{"a [e", "x] b"}, content: []string{"a ", "[ex] b"}},
+		{name: "held back at the end", deltas: []string{"a [en"}, content: []string{"a ", "[en"}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var events []string
+			for _, delta := range tc.deltas {
+				events = append(events, contentEvent(delta))
+			}
+			server := newEventStreamServer(t, append(events, "data: [DONE]")...)
+			stream := openStream(t, server, ChatCompletionRequest{StopSequences: Stop{"[end]"}}, WithStreamMiddleware(EnforceStop()))
+
+			var (
+				content []string
+				finish  string
+			)
+			for stream.Next() {
+				content = append(content, stream.Current().Choices[0].Delta.Content)
+				finish = stream.Current().Choices[0].FinishReason
+			}
+
+			require.NoError(t, stream.Err())
+			assert.Equal(t, tc.content, content)
+			assert.Equal(t, tc.finish, finish)
+		})
+	}
+}
+
+func TestEnforceStopKeepsUsage(t *testing.T) {
+	server := newEventStreamServer(t,
+		contentEvent("Hello [end] world"),
+		contentEvent("!"),
+		`data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{},"finish_reason":"length"}],"x_groq":{"id":"req_1","usage":{"prompt_tokens":5,"completion_tokens":4,"total_tokens":9}}}`,
+		"data: [DONE]",
+	)
+	stream := openStream(t, server, ChatCompletionRequest{StopSequences: Stop{"[end]"}}, WithStreamMiddleware(EnforceStop()))
+
+	var content string
+	for stream.Next() {
+		for _, choice := range stream.Current().Choices {
+			content += choice.Delta.Content
+		}
+	}
+
+	require.NoError(t, stream.Err())
+	assert.Equal(t, "Hello ", content)
+	assert.Equal(t, "stop", stream.FinishReason(0))
+	require.NotNil(t, stream.Usage())
+	assert.Equal(t, 9, stream.Usage().TotalTokens)
+}
+
+func TestEnforceStopTimeoutPartial(t *testing.T) {
+	server := newStallingServer(t, 10*time.Millisecond, "Hello [end] world")
+	stream := openStream(t, server, ChatCompletionRequest{StopSequences: Stop{"[end]"}},
+		WithStreamMiddleware(EnforceStop()), WithIdleTimeout(50*time.Millisecond))
+
+	var content string
+	for stream.Next() {
+		content += stream.Current().Choices[0].Delta.Content
+	}
+	assert.Equal(t, "Hello ", content)
+
+	var timeoutErr *StreamTimeoutError
+	require.True(t, errors.As(stream.Err(), &timeoutErr), "error should be a *StreamTimeoutError")
+	assert.Equal(t, "Hello ", timeoutErr.Partial.Choices[0].Message.Content)
+}
@@ -1,82 +1,368 @@
 package groq
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"iter"
+	"mime"
 	"net/http"
-	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/pkg/errors"
-	"github.com/tmaxmax/go-sse"
 )
 
-type ChatCompletionStreamResponse struct {
-	Response ChatCompletionResponse
-	Error    error
+// ChatCompletionChunk is a chunk of a streamed chat completion. Its choices
+// carry the generated text in Delta rather than Message.
+type ChatCompletionChunk = ChatCompletionResponse
+
+// CreateChatCompletionStream sends a request to create a streamed chat
+// completion, and returns the stream to read its chunks from. The stream
+// must be closed, or read until its end.
+func (c *client) CreateChatCompletionStream(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionStream, error) {
+	req = c.withDefaults(req)
+
+	ctx, cancel := context.WithCancel(ctx)
+	reader, err := c.streamHandler(ctx, req)
+	if err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	stream := &ChatCompletionStream{reader: reader, cancel: cancel}
+	if meta := streamMeta(reader); meta != nil {
+		stream.meta, stream.rateLimit = meta, ParseRateLimitInfo(meta.Header)
+	}
+	if c.firstTokenTimeout > 0 || c.idleTimeout > 0 {
+		stream.partial = &StreamAccumulator{}
+	}
+
+	return stream, nil
+}
+
+// ChatCompletionStream is a streamed chat completion, read chunk by chunk:
+//
+//	defer stream.Close()
+//	for stream.Next() {
+//		chunk := stream.Current()
+//		...
+//	}
+//	if err := stream.Err(); err != nil {
+//		...
+//	}
+//
+// The connection is released once the stream has ended, failed or been
+// closed. A ChatCompletionStream is not safe for concurrent use, except for
+// Close.
+type ChatCompletionStream struct {
+	reader  StreamReader
+	cancel  context.CancelFunc
+	current *ChatCompletionChunk
+	err     error
+	done    bool
+	partial *StreamAccumulator // Output read so far, kept for a *StreamTimeoutError
+
+	closeOnce sync.Once
+	closed    atomic.Bool
+	closeErr  error
+
+	rateLimit     *RateLimitInfo
+	meta          *ResponseMeta
+	usage         *Usage
+	finishReasons map[int]string
+}
+
+// Next advances the stream to the next chunk, which is then available
+// through Current. It returns false once the stream has ended, failed or
+// been closed; Err then returns the error, if any.
+func (s *ChatCompletionStream) Next() bool {
+	if s.done || s.closed.Load() {
+		s.current = nil
+		return false
+	}
+
+	chunk, err := s.reader.Recv()
+	if err != nil {
+		if !errors.Is(err, io.EOF) && !s.closed.Load() {
+			s.err = err
+		}
+		var timeoutErr *StreamTimeoutError
+		if s.partial != nil && errors.As(err, &timeoutErr) {
+			timeoutErr.Partial = s.partial.Response()
+		}
+		s.current = nil
+		s.done = true
+		_ = s.Close()
+
+		return false
+	}
+
+	s.current = chunk
+	if s.partial != nil {
+		s.partial.Add(chunk)
+	}
+	if chunk.RateLimit != nil {
+		s.rateLimit = chunk.RateLimit
+	}
+	if chunk.Meta != nil {
+		s.meta = chunk.Meta
+	}
+	if usage := chunk.streamUsage(); usage != nil {
+		s.usage = usage
+	}
+	for _, choice := range chunk.Choices {
+		if choice.FinishReason != "" {
+			if s.finishReasons == nil {
+				s.finishReasons = make(map[int]string)
+			}
+			s.finishReasons[choice.Index] = choice.FinishReason
+		}
+	}
+
+	return true
+}
+
+// Current returns the chunk read by the last call to Next.
+func (s *ChatCompletionStream) Current() *ChatCompletionChunk {
+	return s.current
+}
+
+// Err returns the error that ended the stream, or nil if it ended normally
+// or was closed.
+func (s *ChatCompletionStream) Err() error {
+	return s.err
+}
+
+// Close stops the stream and releases its connection. It's safe to call
+// more than once.
+func (s *ChatCompletionStream) Close() error {
+	s.closeOnce.Do(func() {
+		s.closed.Store(true)
+		s.cancel()
+		s.closeErr = s.reader.Close()
+	})
+
+	return s.closeErr
+}
+
+// All returns an iterator over the chunks of the stream, for range loops.
+// A failure of the stream is yielded last, with a nil chunk. The stream is
+// closed when the loop ends, even if it ends early.
+func (s *ChatCompletionStream) All() iter.Seq2[*ChatCompletionChunk, error] {
+	return func(yield func(*ChatCompletionChunk, error) bool) {
+		defer func() {
+			_ = s.Close()
+		}()
+
+		for s.Next() {
+			if !yield(s.Current(), nil) {
+				return
+			}
+		}
+		if err := s.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
 }
 
-func (c *client) CreateChatCompletionStream(ctx context.Context, req ChatCompletionRequest) (<-chan *ChatCompletionStreamResponse, func(), error) {
-	if !req.Stream {
-		return nil, nil, fmt.Errorf("stream must be set to true")
+// RateLimit returns the rate limit state reported when the stream was
+// opened.
+func (s *ChatCompletionStream) RateLimit() *RateLimitInfo {
+	return s.rateLimit
+}
+
+// Meta returns the HTTP response the stream is read from.
+func (s *ChatCompletionStream) Meta() *ResponseMeta {
+	return s.meta
+}
+
+// Usage returns the usage of the whole request, or nil until the chunk
+// carrying it, normally the last one, has been read.
+func (s *ChatCompletionStream) Usage() *Usage {
+	return s.usage
+}
+
+// FinishReason returns the reason why the model stopped generating the
+// choice at index, or "" until the chunk carrying it has been read.
+func (s *ChatCompletionStream) FinishReason(index int) string {
+	return s.finishReasons[index]
+}
+
+// createChatCompletionStream is the StreamHandler that opens the stream.
+func (c *client) createChatCompletionStream(ctx context.Context, req ChatCompletionRequest) (StreamReader, error) {
+	if err := req.validate(true); err != nil {
+		return nil, err
 	}
 
-	url := fmt.Sprintf("%s/v1/chat/completions", c.baseURL)
+	reservation, err := c.reserve(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 
-	jsonData, err := json.Marshal(req)
+	ctx, cancel := context.WithCancel(ctx)
+	httpReq, err := c.newRequest(ctx, http.MethodPost, "/v1/chat/completions", req)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to marshal request: %v", err)
+		cancel()
+		reservation.Reconcile(0)
+
+		return nil, err
 	}
 
-	ctxWithCancel, cancel := context.WithCancel(ctx)
-	httpReq, err := http.NewRequestWithContext(ctxWithCancel, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	watchdog := newStreamWatchdog(c.firstTokenTimeout, c.idleTimeout, cancel)
+	watchdog.arm()
+	resp, attempt, err := c.connectStream(httpReq)
+	watchdog.disarm()
 	if err != nil {
 		cancel()
+		reservation.Reconcile(0)
+		if timeoutErr := watchdog.err(nil); timeoutErr != nil {
+			return nil, timeoutErr
+		}
+
+		return nil, err
+	}
+
+	meta := attempt.meta(resp)
+
+	return &eventStreamReader{
+		resp:         resp,
+		decoder:      newSSEDecoder(resp.Body),
+		cancel:       cancel,
+		watchdog:     watchdog,
+		reservation:  reservation,
+		promptTokens: estimatePromptTokens(req.Messages),
+		rateLimit:    ParseRateLimitInfo(meta.Header),
+		meta:         meta,
+		keyID:        c.keyID(attempt.key),
+	}, nil
+}
 
-		return nil, nil, fmt.Errorf("failed to create request: %v", err)
+// connectStream sends httpReq and returns the accepted event stream
+// response. Failures are retried according to the client's retry policy
+// until a response is accepted; the request is never sent again after that.
+func (c *client) connectStream(httpReq *http.Request) (*http.Response, *requestAttempt, error) {
+	resp, attempt, err := c.send(httpReq)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	if err := validateStreamResponse(resp); err != nil {
+		_ = resp.Body.Close()
+
+		return nil, nil, err
+	}
 
-	responseCh := make(chan *ChatCompletionStreamResponse)
+	return resp, attempt, nil
+}
 
-	cli := &sse.Client{
-		HTTPClient:        c.client,
-		ResponseValidator: sse.DefaultValidator,
-		Backoff:           sse.DefaultClient.Backoff,
+// validateStreamResponse rejects responses that aren't event streams.
+func validateStreamResponse(resp *http.Response) error {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || mediaType != "text/event-stream" {
+		return fmt.Errorf("expected an event stream, got Content-Type %q", resp.Header.Get("Content-Type"))
 	}
 
-	conn := cli.NewConnection(httpReq)
-	go func() {
-		defer close(responseCh)
+	return nil
+}
 
-		err := conn.Connect()
-		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, context.Canceled) {
-			responseCh <- &ChatCompletionStreamResponse{
-				Error: errors.Wrap(err, "failed to connect to the server"),
+// eventStreamReader is the StreamReader decoding the chunks of an open event
+// stream. It reads the response directly, without a goroutine of its own.
+type eventStreamReader struct {
+	resp        *http.Response
+	decoder     *sseDecoder
+	cancel      context.CancelFunc
+	watchdog    *streamWatchdog
+	reservation *Reservation
+	// promptTokens and generatedChars estimate the tokens used by the
+	// request if the stream ends without reporting its usage.
+	promptTokens   int
+	generatedChars atomic.Int64
+	rateLimit      *RateLimitInfo
+	meta           *ResponseMeta
+	keyID          string // Redacted key the stream was opened with, for errors
+	usage          atomic.Pointer[Usage]
+	done           bool
+
+	closeOnce sync.Once
+}
+
+func (r *eventStreamReader) Recv() (*ChatCompletionResponse, error) {
+	if r.done {
+		return nil, io.EOF
+	}
+
+	for {
+		r.watchdog.arm()
+		event, err := r.decoder.Next()
+		r.watchdog.disarm()
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			r.done = true
+			_ = r.Close()
+			if timeoutErr := r.watchdog.err(nil); timeoutErr != nil {
+				return nil, timeoutErr
 			}
+
+			return nil, errors.Wrap(err, "failed to read the stream")
+		}
+
+		switch event.Type {
+		case "message":
+		case "error":
+			r.done = true
+			_ = r.Close()
+
+			return nil, newStreamAPIError(r.resp, []byte(event.Data), r.keyID)
+		default:
+			continue
 		}
-	}()
 
-	remover := conn.SubscribeToAll(func(event sse.Event) {
-		if strings.Contains(event.Data, "DONE") {
-			cancel()
-			return
+		if event.Data == "[DONE]" {
+			r.done = true
+			_ = r.Close()
+
+			return nil, io.EOF
 		}
 
 		var chatResp ChatCompletionResponse
 		if err := json.Unmarshal([]byte(event.Data), &chatResp); err != nil {
-			responseCh <- &ChatCompletionStreamResponse{Error: errors.Wrap(err, "failed to unmarshal response")}
-			return
+			return nil, errors.Wrap(err, "failed to unmarshal response")
 		}
 
-		responseCh <- &ChatCompletionStreamResponse{Response: chatResp}
+		if u := chatResp.streamUsage(); u != nil {
+			r.usage.Store(u)
+		}
+		chatResp.RateLimit, chatResp.Meta = r.rateLimit, r.meta
+
+		r.watchdog.received(&chatResp)
+		for _, choice := range chatResp.Choices {
+			r.generatedChars.Add(int64(len(choice.Delta.Content) + toolCallChars(choice.Delta.ToolCalls)))
+		}
+
+		return &chatResp, nil
+	}
+}
+
+func (r *eventStreamReader) Meta() *ResponseMeta {
+	return r.meta
+}
+
+func (r *eventStreamReader) Close() error {
+	r.closeOnce.Do(func() {
+		r.watchdog.disarm()
+		r.cancel()
+		_ = r.resp.Body.Close()
+		if usage := r.usage.Load(); usage != nil {
+			r.reservation.Reconcile(usage.TotalTokens)
+		} else {
+			r.reservation.Reconcile(r.promptTokens + estimateTextTokens(int(r.generatedChars.Load())))
+		}
 	})
 
-	return responseCh, func() {
-		cancel()
-		remover()
-	}, nil
+	return nil
 }
@@ -0,0 +1,191 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatCompletionRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, &StreamOptions{IncludeUsage: true}, req.StreamOptions)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"role":"assistant","content":"Hi"}}],"x_groq":{"id":"req_123"}}`+"\n\n")
+		fmt.Fprint(w, `data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{},"finish_reason":"stop"}],"x_groq":{"id":"req_123","usage":{"queue_time":0.01,"prompt_tokens":10,"completion_tokens":1,"total_tokens":11}}}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	stream := openStream(t, server, ChatCompletionRequest{StreamOptions: &StreamOptions{IncludeUsage: true}})
+
+	require.True(t, stream.Next())
+	assert.Equal(t, "req_123", stream.Current().XGroq.ID)
+	assert.Nil(t, stream.Usage())
+
+	require.True(t, stream.Next())
+	assert.False(t, stream.Next())
+	require.NoError(t, stream.Err())
+	assert.Equal(t, &Usage{PromptTokens: 10, CompletionTokens: 1, TotalTokens: 11, QueueTime: 0.01}, stream.Usage())
+}
+
+func TestStreamAllClosesOnBreak(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; ; i++ {
+			if _, err := fmt.Fprintf(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"%d\"}}]}\n\n", i); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(time.Millisecond):
+			}
+		}
+	}))
+	defer server.Close()
+
+	stream := openStream(t, server, ChatCompletionRequest{})
+
+	var chunks int
+	for _, err := range stream.All() {
+		require.NoError(t, err)
+		if chunks++; chunks == 3 {
+			break
+		}
+	}
+
+	assert.False(t, stream.Next(), "the stream should be closed after the loop")
+	assert.NoError(t, stream.Err())
+}
+
+func TestStreamErr(t *testing.T) {
+	server := newEventStreamServer(t, "data: {not json}")
+	stream := openStream(t, server, ChatCompletionRequest{})
+
+	var errs []error
+	for chunk, err := range stream.All() {
+		assert.Nil(t, chunk)
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "failed to unmarshal response")
+	assert.Equal(t, errs[0], stream.Err())
+}
+
+func TestLegacyStream(t *testing.T) {
+	server := newEventStreamServer(t, contentEvent("0"), contentEvent("1"), contentEvent("2"), "data: [DONE]")
+
+	c := NewLegacyClient("test-key", WithBaseURL(server.URL))
+	respCh, closer, err := c.CreateChatCompletionStream(context.Background(), ChatCompletionRequest{
+		Messages: []Message{{Role: MessageRoleUser, Content: "count"}},
+		Stream:   true,
+	})
+	require.NoError(t, err)
+	defer closer()
+
+	var content string
+	for resp := range respCh {
+		require.NoError(t, resp.Error)
+		content += resp.Response.Choices[0].Delta.Content
+	}
+	assert.Equal(t, "012", content)
+}
+
+func TestStreamDoneSentinel(t *testing.T) {
+	server := newEventStreamServer(t, contentEvent("DONE"), contentEvent(" [DONE] and more"), "data: [DONE]")
+	stream := openStream(t, server, ChatCompletionRequest{})
+
+	text, err := io.ReadAll(stream.TextReader(0))
+	require.NoError(t, err)
+	assert.Equal(t, "DONE [DONE] and more", string(text))
+}
+
+func TestStreamErrorEvent(t *testing.T) {
+	server := newEventStreamServer(t,
+		contentEvent("Hi"),
+		"event: error\n"+`data: {"error":{"message":"Service Unavailable","type":"internal_server_error","code":"service_unavailable"}}`,
+	)
+	stream := openStream(t, server, ChatCompletionRequest{})
+
+	require.True(t, stream.Next())
+	require.False(t, stream.Next())
+
+	apiErr, ok := asAPIError(stream.Err())
+	require.True(t, ok, "error should be an *APIError")
+	assert.Equal(t, "service_unavailable", apiErr.Code)
+	assert.Equal(t, "Service Unavailable", apiErr.Message)
+	assert.True(t, apiErr.Stream)
+	assert.Zero(t, apiErr.StatusCode)
+	assert.EqualError(t, stream.Err(), "groq: stream error (service_unavailable): Service Unavailable")
+}
+
+func TestStreamIsNotResent(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, contentEvent("Hi")+"\n\n")
+	}))
+	defer server.Close()
+
+	stream := openStream(t, server, ChatCompletionRequest{}, WithRetryPolicy(RetryPolicy{MaxAttempts: 3}))
+
+	require.True(t, stream.Next())
+	require.False(t, stream.Next())
+	assert.ErrorIs(t, stream.Err(), io.ErrUnexpectedEOF)
+	assert.EqualValues(t, 1, requests.Load())
+}
+
+func TestStreamOpenError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"message":"Invalid API Key","type":"invalid_request_error","code":"invalid_api_key"}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("test-key", WithBaseURL(server.URL))
+	_, err := c.CreateChatCompletionStream(context.Background(), ChatCompletionRequest{
+		Messages: []Message{{Role: MessageRoleUser, Content: "hi"}},
+		Stream:   true,
+	})
+	assert.True(t, IsAuthError(err))
+}
+
+func TestStreamMetaBeforeFirstChunk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("x-request-id", "req_1")
+		w.Header().Set("x-ratelimit-remaining-requests", "9")
+		fmt.Fprint(w, "event: error\n"+`data: {"error":{"message":"Service Unavailable","type":"internal_server_error"}}`+"\n\n")
+	}))
+	defer server.Close()
+
+	stream := openStream(t, server, ChatCompletionRequest{StopSequences: Stop{"[end]"}}, WithStreamMiddleware(EnforceStop()))
+
+	require.NotNil(t, stream.Meta())
+	assert.Equal(t, "req_1", stream.Meta().RequestID)
+	require.NotNil(t, stream.RateLimit())
+	assert.Equal(t, 9, stream.RateLimit().RemainingRequests)
+
+	require.False(t, stream.Next())
+	require.Error(t, stream.Err())
+	assert.Equal(t, "req_1", stream.Meta().RequestID)
+}
@@ -0,0 +1,94 @@
+package groq
+
+import (
+	"io"
+	"net/http"
+)
+
+// TextReader returns a reader of the text generated for the choice at
+// index, as it's streamed. Reading it advances the stream, which must not be
+// read otherwise meanwhile. Copying it with io.Copy flushes writers that
+// implement http.Flusher after each chunk. Once it returns io.EOF, the
+// stream's FinishReason and Usage report how the generation ended.
+func (s *ChatCompletionStream) TextReader(index int) io.Reader {
+	return &textReader{stream: s, index: index}
+}
+
+// WriteTo writes the text generated for the first choice to w as it's
+// streamed, flushing w after each chunk if it implements http.Flusher, e.g.
+// to relay the completion to an HTTP client. It returns once the stream has
+// ended, with the error that ended it, if any.
+func (s *ChatCompletionStream) WriteTo(w io.Writer) (int64, error) {
+	return s.TextReader(0).(io.WriterTo).WriteTo(w)
+}
+
+// textReader is the reader returned by ChatCompletionStream.TextReader.
+type textReader struct {
+	stream *ChatCompletionStream
+	index  int
+	buf    string // Text of the current chunk that wasn't read yet
+}
+
+func (r *textReader) Read(p []byte) (int, error) {
+	for r.buf == "" {
+		text, err := r.next()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = text
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
+
+func (r *textReader) WriteTo(w io.Writer) (int64, error) {
+	flusher, _ := w.(http.Flusher)
+
+	var written int64
+	for {
+		if r.buf != "" {
+			n, err := io.WriteString(w, r.buf)
+			written += int64(n)
+			r.buf = r.buf[n:]
+			if err != nil {
+				return written, err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+
+		text, err := r.next()
+		if err == io.EOF {
+			return written, nil
+		}
+		if err != nil {
+			return written, err
+		}
+		r.buf = text
+	}
+}
+
+// next advances the stream to the next chunk, returning its text for the
+// reader's choice, or io.EOF once the stream has ended.
+func (r *textReader) next() (string, error) {
+	if !r.stream.Next() {
+		if err := r.stream.Err(); err != nil {
+			return "", err
+		}
+
+		return "", io.EOF
+	}
+
+	var text string
+	for _, choice := range r.stream.Current().Choices {
+		if choice.Index == r.index {
+			text += choice.Delta.Content
+		}
+	}
+
+	return text, nil
+}
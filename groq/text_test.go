@@ -0,0 +1,47 @@
+package groq
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTextStream(t *testing.T, deltas ...string) *ChatCompletionStream {
+	var events []string
+	for _, delta := range deltas {
+		events = append(events, fmt.Sprintf(`data: {"choices":[{"index":0,"delta":{"content":%q}},{"index":1,"delta":{"content":"-"}}]}`, delta))
+	}
+	events = append(events,
+		`data: {"choices":[{"index":0,"delta":{},"finish_reason":"length"}],"x_groq":{"id":"req_123","usage":{"total_tokens":7}}}`,
+		"data: [DONE]",
+	)
+
+	return openStream(t, newEventStreamServer(t, events...), ChatCompletionRequest{})
+}
+
+func TestTextReader(t *testing.T) {
+	stream := newTextStream(t, "Hello", ", ", "world")
+
+	text, err := io.ReadAll(stream.TextReader(1))
+	require.NoError(t, err)
+	assert.Equal(t, "---", string(text))
+	assert.Equal(t, "length", stream.FinishReason(0))
+	assert.Equal(t, 7, stream.Usage().TotalTokens)
+}
+
+func TestWriteTo(t *testing.T) {
+	stream := newTextStream(t, "Hello", ", ", "world")
+
+	rec := httptest.NewRecorder()
+	n, err := stream.WriteTo(rec)
+	require.NoError(t, err)
+	assert.EqualValues(t, 12, n)
+	assert.Equal(t, "Hello, world", rec.Body.String())
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, "length", stream.FinishReason(0))
+	assert.Equal(t, 7, stream.Usage().TotalTokens)
+}
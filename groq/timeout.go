@@ -0,0 +1,172 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// StreamTimeoutPhase is the wait of a stream that timed out.
+type StreamTimeoutPhase int
+
+const (
+	// StreamTimeoutFirstToken is the wait for the first token, from sending
+	// the request.
+	StreamTimeoutFirstToken StreamTimeoutPhase = iota + 1
+	// StreamTimeoutIdle is the wait for the next chunk, once the first token
+	// has arrived or from sending the request if there is no first token
+	// timeout.
+	StreamTimeoutIdle
+)
+
+// StreamTimeoutError is returned when a stream is aborted by the timeout
+// set with WithFirstTokenTimeout or WithIdleTimeout. Only the time spent
+// waiting on the server counts toward those timeouts, not the time the caller
+// spends between calls to Recv. It matches context.DeadlineExceeded with
+// errors.Is.
+type StreamTimeoutError struct {
+	Phase    StreamTimeoutPhase
+	Duration time.Duration // The timeout that was exceeded
+	// Partial is the output read from the stream before the timeout, as
+	// rebuilt by a StreamAccumulator, after any StreamMiddleware; nil if the
+	// stream timed out before being opened.
+	Partial *ChatCompletionResponse
+}
+
+func (e *StreamTimeoutError) Error() string {
+	if e.Phase == StreamTimeoutIdle {
+		return fmt.Sprintf("groq: stream idle for %v", e.Duration)
+	}
+
+	return fmt.Sprintf("groq: no token within %v", e.Duration)
+}
+
+// Timeout reports that the error is a timeout, as net.Error does.
+func (e *StreamTimeoutError) Timeout() bool {
+	return true
+}
+
+func (e *StreamTimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// streamWatchdog cancels a stream when the client waits too long for its
+// first token or its next chunk. It is armed while the request is being sent
+// and while Recv is blocked reading. A nil streamWatchdog never fires.
+type streamWatchdog struct {
+	firstToken time.Duration
+	idle       time.Duration
+	cancel     context.CancelFunc
+
+	mu      sync.Mutex
+	timer   *time.Timer
+	armedAt time.Time
+	waited  time.Duration // Time spent waiting before the first token, which the first token timeout bounds in total
+	started bool          // Whether the first token has arrived
+	fired   atomic.Int32  // StreamTimeoutPhase that fired, if any
+}
+
+// newStreamWatchdog returns a watchdog canceling the stream through cancel,
+// or nil if both timeouts are zero.
+func newStreamWatchdog(firstToken, idle time.Duration, cancel context.CancelFunc) *streamWatchdog {
+	if firstToken <= 0 && idle <= 0 {
+		return nil
+	}
+
+	return &streamWatchdog{firstToken: firstToken, idle: idle, cancel: cancel}
+}
+
+// arm starts the timeout of the wait that begins: the rest of the first
+// token timeout until the first token has arrived, and the idle timeout
+// otherwise, or before the first token if there is no first token timeout.
+func (w *streamWatchdog) arm() {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var (
+		phase   StreamTimeoutPhase
+		timeout time.Duration
+	)
+	switch {
+	case !w.started && w.firstToken > 0:
+		phase, timeout = StreamTimeoutFirstToken, max(w.firstToken-w.waited, 0)
+	case w.idle > 0:
+		phase, timeout = StreamTimeoutIdle, w.idle
+	default:
+		return
+	}
+
+	w.armedAt = time.Now()
+	w.timer = time.AfterFunc(timeout, func() { w.fire(phase) })
+}
+
+// disarm stops the timeout of the wait that ended.
+func (w *streamWatchdog) disarm() {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timer == nil {
+		return
+	}
+	w.timer.Stop()
+	w.timer = nil
+	if !w.started {
+		w.waited += time.Since(w.armedAt)
+	}
+}
+
+func (w *streamWatchdog) fire(phase StreamTimeoutPhase) {
+	w.fired.CompareAndSwap(0, int32(phase))
+	w.cancel()
+}
+
+// received records the arrival of chunk, switching to the idle timeout once
+// it carries the first token.
+func (w *streamWatchdog) received(chunk *ChatCompletionChunk) {
+	if w == nil || !hasToken(chunk) {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.started = true
+}
+
+// err returns the timeout error of the stream with its partial output, or
+// nil if the watchdog hasn't fired.
+func (w *streamWatchdog) err(partial *ChatCompletionResponse) error {
+	if w == nil {
+		return nil
+	}
+
+	switch phase := StreamTimeoutPhase(w.fired.Load()); phase {
+	case StreamTimeoutFirstToken:
+		return &StreamTimeoutError{Phase: phase, Duration: w.firstToken, Partial: partial}
+	case StreamTimeoutIdle:
+		return &StreamTimeoutError{Phase: phase, Duration: w.idle, Partial: partial}
+	default:
+		return nil
+	}
+}
+
+// hasToken reports whether chunk carries generated output.
+func hasToken(chunk *ChatCompletionChunk) bool {
+	for _, choice := range chunk.Choices {
+		if choice.Delta.Content != "" || len(choice.Delta.ToolCalls) > 0 || choice.FinishReason != "" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,134 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readStream(t *testing.T, c Client) (string, error) {
+	stream, err := c.CreateChatCompletionStream(context.Background(), ChatCompletionRequest{
+		Messages: []Message{{Role: MessageRoleUser, Content: "hi"}},
+		Stream:   true,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	var content string
+	for stream.Next() {
+		content += stream.Current().Choices[0].Delta.Content
+	}
+
+	return content, stream.Err()
+}
+
+func TestFirstTokenTimeout(t *testing.T) {
+	server := newStallingServer(t, time.Second, "late")
+	c := NewClient("test-key", WithBaseURL(server.URL), WithFirstTokenTimeout(50*time.Millisecond))
+
+	_, err := readStream(t, c)
+
+	var timeoutErr *StreamTimeoutError
+	require.True(t, errors.As(err, &timeoutErr), "error should be a *StreamTimeoutError")
+	assert.Equal(t, StreamTimeoutFirstToken, timeoutErr.Phase)
+	assert.Equal(t, 50*time.Millisecond, timeoutErr.Duration)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	require.NotNil(t, timeoutErr.Partial)
+	assert.Equal(t, MessageRoleAssistant, timeoutErr.Partial.Choices[0].Message.Role)
+}
+
+func TestFirstTokenTimeoutBeforeResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		// The server only notices the client going away once the body is read.
+		_, _ = io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	c := NewClient("test-key", WithBaseURL(server.URL), WithFirstTokenTimeout(50*time.Millisecond))
+
+	_, err := readStream(t, c)
+
+	var timeoutErr *StreamTimeoutError
+	require.True(t, errors.As(err, &timeoutErr), "error should be a *StreamTimeoutError")
+	assert.Equal(t, StreamTimeoutFirstToken, timeoutErr.Phase)
+	assert.Nil(t, timeoutErr.Partial)
+}
+
+func TestIdleTimeout(t *testing.T) {
+	server := newStallingServer(t, 10*time.Millisecond, "Hel", "lo")
+	c := NewClient("test-key", WithBaseURL(server.URL), WithFirstTokenTimeout(time.Second), WithIdleTimeout(100*time.Millisecond))
+
+	content, err := readStream(t, c)
+	assert.Equal(t, "Hello", content)
+
+	var timeoutErr *StreamTimeoutError
+	require.True(t, errors.As(err, &timeoutErr), "error should be a *StreamTimeoutError")
+	assert.Equal(t, StreamTimeoutIdle, timeoutErr.Phase)
+	assert.EqualError(t, err, "groq: stream idle for 100ms")
+	assert.Equal(t, "Hello", timeoutErr.Partial.Choices[0].Message.Content)
+}
+
+func TestStreamWithinTimeouts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, delta := range []string{"a", "b", "c"} {
+			time.Sleep(20 * time.Millisecond)
+			fmt.Fprint(w, contentEvent(delta)+"\n\n")
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	c := NewClient("test-key", WithBaseURL(server.URL), WithFirstTokenTimeout(time.Second), WithIdleTimeout(time.Second))
+
+	content, err := readStream(t, c)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", content)
+}
+
+func TestIdleTimeoutWithoutFirstTokenTimeout(t *testing.T) {
+	server := newStallingServer(t, 0)
+	c := NewClient("test-key", WithBaseURL(server.URL), WithIdleTimeout(50*time.Millisecond))
+
+	_, err := readStream(t, c)
+
+	var timeoutErr *StreamTimeoutError
+	require.True(t, errors.As(err, &timeoutErr), "error should be a *StreamTimeoutError")
+	assert.Equal(t, StreamTimeoutIdle, timeoutErr.Phase)
+}
+
+func TestTimeoutsIgnoreSlowConsumer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, delta := range []string{"a", "b", "c"} {
+			time.Sleep(30 * time.Millisecond)
+			fmt.Fprint(w, contentEvent(delta)+"\n\n")
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	stream := openStream(t, server, ChatCompletionRequest{}, WithFirstTokenTimeout(50*time.Millisecond), WithIdleTimeout(50*time.Millisecond))
+
+	var content string
+	for stream.Next() {
+		content += stream.Current().Choices[0].Delta.Content
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	require.NoError(t, stream.Err())
+	assert.Equal(t, "abc", content)
+}
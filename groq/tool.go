@@ -0,0 +1,131 @@
+package groq
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+type ToolType string
+
+const (
+	ToolTypeFunction ToolType = "function"
+)
+
+// Tool represents a tool the model may call.
+type Tool struct {
+	Type     ToolType           `json:"type"`     // The type of the tool. Currently, only function is supported.
+	Function FunctionDefinition `json:"function"` // The function the model may call
+}
+
+// FunctionDefinition describes a function the model may call.
+type FunctionDefinition struct {
+	Name        string `json:"name"`                  // The name of the function to be called. Must be a-z, A-Z, 0-9, or contain underscores and dashes, with a maximum length of 64.
+	Description string `json:"description,omitempty"` // A description of what the function does, used by the model to choose when and how to call the function.
+	Parameters  any    `json:"parameters,omitempty"`  // The parameters the function accepts, described as a JSON Schema object. Decoded requests hold it as a map[string]any.
+	Strict      bool   `json:"strict,omitempty"`      // Whether the model must follow the exact schema defined in Parameters.
+}
+
+// NewFunctionTool returns a function Tool.
+func NewFunctionTool(name, description string, parameters any) Tool {
+	return Tool{
+		Type: ToolTypeFunction,
+		Function: FunctionDefinition{
+			Name:        name,
+			Description: description,
+			Parameters:  parameters,
+		},
+	}
+}
+
+type ToolChoiceMode string
+
+const (
+	ToolChoiceModeNone     ToolChoiceMode = "none"     // The model will not call any tool and instead generates a message.
+	ToolChoiceModeAuto     ToolChoiceMode = "auto"     // The model can pick between generating a message or calling one or more tools.
+	ToolChoiceModeRequired ToolChoiceMode = "required" // The model must call one or more tools.
+)
+
+// ToolChoice controls which tool is called by the model: either one of the
+// modes, or a specific function. It's sent as a string for a mode, and as
+// {"type": "function", "function": {"name": "my_function"}} for a function.
+type ToolChoice struct {
+	Mode     ToolChoiceMode // Set for a mode choice
+	Function string         // Name of the function the model must call; takes precedence over Mode
+}
+
+// NewToolChoice returns a ToolChoice of the given mode.
+func NewToolChoice(mode ToolChoiceMode) *ToolChoice {
+	return &ToolChoice{Mode: mode}
+}
+
+// NewToolChoiceFunction returns a ToolChoice forcing the model to call the
+// named function.
+func NewToolChoiceFunction(name string) *ToolChoice {
+	return &ToolChoice{Function: name}
+}
+
+type toolChoiceFunction struct {
+	Type     ToolType     `json:"type"`
+	Function functionName `json:"function"`
+}
+
+type functionName struct {
+	Name string `json:"name"`
+}
+
+func (c ToolChoice) MarshalJSON() ([]byte, error) {
+	if c.Function != "" {
+		return json.Marshal(toolChoiceFunction{Type: ToolTypeFunction, Function: functionName{Name: c.Function}})
+	}
+
+	return json.Marshal(c.Mode)
+}
+
+func (c *ToolChoice) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		*c = ToolChoice{}
+		return json.Unmarshal(data, &c.Mode)
+	}
+
+	var choice toolChoiceFunction
+	if err := json.Unmarshal(data, &choice); err != nil {
+		return fmt.Errorf("tool_choice must be a string or a function object: %w", err)
+	}
+	*c = ToolChoice{Function: choice.Function.Name}
+
+	return nil
+}
+
+// FunctionCallChoice controls which function is called by the model: "none",
+// "auto", or a specific function. It's sent as a string for a mode, and as
+// {"name": "my_function"} for a function.
+//
+// Deprecated: Use Tools and ToolChoice.
+type FunctionCallChoice struct {
+	Mode     ToolChoiceMode // Set for a mode choice; only none and auto are supported
+	Function string         // Name of the function the model must call; takes precedence over Mode
+}
+
+func (c FunctionCallChoice) MarshalJSON() ([]byte, error) {
+	if c.Function != "" {
+		return json.Marshal(functionName{Name: c.Function})
+	}
+
+	return json.Marshal(c.Mode)
+}
+
+func (c *FunctionCallChoice) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		*c = FunctionCallChoice{}
+		return json.Unmarshal(data, &c.Mode)
+	}
+
+	var name functionName
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("function_call must be a string or a function object: %w", err)
+	}
+	*c = FunctionCallChoice{Function: name.Name}
+
+	return nil
+}
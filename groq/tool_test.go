@@ -0,0 +1,72 @@
+package groq
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolChoiceJSON(t *testing.T) {
+	testcases := []struct {
+		name   string
+		choice *ToolChoice
+		json   string
+	}{
+		{name: "auto", choice: NewToolChoice(ToolChoiceModeAuto), json: `"auto"`},
+		{name: "none", choice: NewToolChoice(ToolChoiceModeNone), json: `"none"`},
+		{name: "required", choice: NewToolChoice(ToolChoiceModeRequired), json: `"required"`},
+		{name: "function", choice: NewToolChoiceFunction("get_weather"), json: `{"type":"function","function":{"name":"get_weather"}}`},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.choice)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.json, string(data))
+
+			var decoded ToolChoice
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, *tc.choice, decoded)
+		})
+	}
+}
+
+func TestToolsRequestJSON(t *testing.T) {
+	req := ChatCompletionRequest{
+		Model: ModelIDLLAMA370B,
+		Tools: []Tool{
+			NewFunctionTool("get_weather", "Get the current weather", map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"location": map[string]any{"type": "string"},
+				},
+				"required": []any{"location"},
+			}),
+		},
+		ToolChoice:   NewToolChoiceFunction("get_weather"),
+		FunctionCall: &FunctionCallChoice{Mode: ToolChoiceModeAuto},
+	}
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"messages": null,
+		"model": "llama3-70b-8192",
+		"tools": [{
+			"type": "function",
+			"function": {
+				"name": "get_weather",
+				"description": "Get the current weather",
+				"parameters": {"type": "object", "properties": {"location": {"type": "string"}}, "required": ["location"]}
+			}
+		}],
+		"tool_choice": {"type": "function", "function": {"name": "get_weather"}},
+		"function_call": "auto"
+	}`, string(data))
+
+	var decoded ChatCompletionRequest
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, req, decoded)
+}
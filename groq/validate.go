@@ -0,0 +1,134 @@
+package groq
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FieldError is a problem with a field of a request. Field is the path of
+// the field in the request body, e.g. "messages[2].tool_call_id".
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError is returned for requests the API would reject, before
+// sending them. It lists every problem found; use errors.As with a
+// *FieldError to get the first one.
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		problems = append(problems, err.Error())
+	}
+
+	return "groq: invalid request: " + strings.Join(problems, "; ")
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// Validate checks the request for problems the API would reject it for,
+// returning a *ValidationError listing all of them.
+func (r ChatCompletionRequest) Validate() error {
+	return r.validate(r.Stream)
+}
+
+// validate checks the request for problems, for a method that streams if
+// stream is set. The client's own Handler and StreamHandler call it, after
+// any middleware.
+func (r ChatCompletionRequest) validate(stream bool) error {
+	var errs []*FieldError
+	add := func(field string, err error) {
+		errs = append(errs, &FieldError{Field: field, Err: err})
+	}
+
+	if len(r.Messages) == 0 {
+		add("messages", errors.New("must not be empty"))
+	}
+
+	toolCalls := make(map[string]bool)
+	for i, message := range r.Messages {
+		if message.Role == MessageRoleAssistant {
+			for _, call := range message.ToolCalls {
+				if call.ID != "" {
+					toolCalls[call.ID] = true
+				}
+			}
+		}
+
+		if message.Role == MessageRoleTool && !toolCalls[message.ToolCallID] {
+			add(fmt.Sprintf("messages[%d].tool_call_id", i), fmt.Errorf("%q doesn't match a tool call of a previous assistant message", message.ToolCallID))
+		}
+	}
+
+	if limit, ok := modelMaxTokens[r.Model]; ok && r.MaxTokens > limit {
+		add("max_tokens", fmt.Errorf("%d exceeds the limit of %d tokens of %s", r.MaxTokens, limit, r.Model))
+	}
+
+	if r.Temperature != nil && (*r.Temperature < 0 || *r.Temperature > 2) {
+		add("temperature", fmt.Errorf("%v is not between 0 and 2", *r.Temperature))
+	}
+
+	if r.NumChoices != nil && *r.NumChoices > 1 {
+		add("n", fmt.Errorf("%d is not supported, only 1 is", *r.NumChoices))
+	}
+
+	if r.TopLogprobs != nil {
+		if *r.TopLogprobs < 0 || *r.TopLogprobs > 20 {
+			add("top_logprobs", fmt.Errorf("%d is not between 0 and 20", *r.TopLogprobs))
+		}
+		if !r.Logprobs {
+			add("top_logprobs", errors.New("requires logprobs"))
+		}
+	}
+
+	if len(r.StopSequences) > maxStopSequences {
+		add("stop", fmt.Errorf("has %d sequences, at most %d are allowed", len(r.StopSequences), maxStopSequences))
+	}
+	for i, sequence := range r.StopSequences {
+		if sequence == "" {
+			add(fmt.Sprintf("stop[%d]", i), errors.New("must not be empty"))
+		}
+	}
+
+	if err := validateResponseFormat(r); err != nil {
+		add("response_format", err)
+	}
+
+	if r.StreamOptions != nil && !r.Stream {
+		add("stream_options", errors.New("requires stream"))
+	}
+
+	if r.Stream != stream {
+		if stream {
+			add("stream", errors.New("must be set to true for CreateChatCompletionStream"))
+		} else {
+			add("stream", errors.New("must not be set for CreateChatCompletion; use CreateChatCompletionStream"))
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+
+	return nil
+}
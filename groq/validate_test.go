@@ -0,0 +1,139 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	req := ChatCompletionRequest{
+		Model:     ModelIDLLAMA370B,
+		MaxTokens: 10000,
+		Messages: []Message{
+			{Role: MessageRoleUser, Content: "What's the weather in Seoul?"},
+			{Role: MessageRoleAssistant, ToolCalls: []ToolCall{{ID: "call_1", Type: ToolTypeFunction}}},
+			NewToolMessage("call_1", "sunny"),
+			NewToolMessage("call_2", "rainy"),
+		},
+		Temperature:   Ptr(2.5),
+		NumChoices:    Ptr(2),
+		StopSequences: []string{"a", "b", "c", "d", "e"},
+		Stream:        true,
+	}
+
+	err := req.validate(false)
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr), "error should be a *ValidationError")
+
+	var fields []string
+	for _, fieldErr := range validationErr.Errors {
+		fields = append(fields, fieldErr.Field)
+	}
+	assert.Equal(t, []string{"messages[3].tool_call_id", "max_tokens", "temperature", "n", "stop", "stream"}, fields)
+	assert.Contains(t, err.Error(), "max_tokens: 10000 exceeds the limit of 8192 tokens of llama3-70b-8192")
+
+	var fieldErr *FieldError
+	require.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, "messages[3].tool_call_id", fieldErr.Field)
+
+	req = ChatCompletionRequest{
+		Model:       ModelIDLLAMA370B,
+		Messages:    []Message{{Role: MessageRoleUser, Content: "hi"}},
+		Temperature: Ptr(0.0),
+		NumChoices:  Ptr(1),
+	}
+	assert.NoError(t, req.Validate())
+	req.NumChoices = Ptr(0)
+	assert.NoError(t, req.Validate())
+	assert.EqualError(t, ChatCompletionRequest{}.Validate(), "groq: invalid request: messages: must not be empty")
+}
+
+func TestValidateToolCallIDs(t *testing.T) {
+	testcases := []struct {
+		name     string
+		messages []Message
+		valid    bool
+	}{
+		{
+			name: "assistant call",
+			messages: []Message{
+				{Role: MessageRoleAssistant, ToolCalls: []ToolCall{{ID: "call_1", Type: ToolTypeFunction}}},
+				NewToolMessage("call_1", "sunny"),
+			},
+			valid: true,
+		},
+		{
+			name: "call of another role",
+			messages: []Message{
+				{Role: MessageRoleUser, ToolCalls: []ToolCall{{ID: "call_1", Type: ToolTypeFunction}}},
+				NewToolMessage("call_1", "sunny"),
+			},
+		},
+		{
+			name: "empty ID",
+			messages: []Message{
+				{Role: MessageRoleAssistant, ToolCalls: []ToolCall{{Type: ToolTypeFunction}}},
+				NewToolMessage("", "sunny"),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ChatCompletionRequest{Messages: tc.messages}.Validate()
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, "messages[1].tool_call_id")
+			}
+		})
+	}
+}
+
+func TestValidateBeforeSending(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("invalid requests should not be sent")
+	}))
+	defer server.Close()
+
+	c := NewClient("test-key", WithBaseURL(server.URL))
+
+	_, err := c.CreateChatCompletion(context.Background(), ChatCompletionRequest{Stream: true})
+	assert.EqualError(t, err, "groq: invalid request: messages: must not be empty; stream: must not be set for CreateChatCompletion; use CreateChatCompletionStream")
+
+	_, err = c.CreateChatCompletionStream(context.Background(), ChatCompletionRequest{
+		Messages: []Message{{Role: MessageRoleUser, Content: "hi"}},
+	})
+	assert.EqualError(t, err, "groq: invalid request: stream: must be set to true for CreateChatCompletionStream")
+}
+
+func TestValidateAfterMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"{}"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	jsonPrompt := func(next Handler) Handler {
+		return func(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error) {
+			req.Messages = append([]Message{{Role: MessageRoleSystem, Content: "Answer in JSON."}}, req.Messages...)
+			return next(ctx, req)
+		}
+	}
+	c := NewClient("test-key", WithBaseURL(server.URL), WithMiddleware(jsonPrompt))
+
+	resp, err := c.CreateChatCompletion(context.Background(), ChatCompletionRequest{
+		Model:          ModelIDLLAMA370B,
+		Messages:       []Message{{Role: MessageRoleUser, Content: "hi"}},
+		ResponseFormat: ResponseFormatJSONObject{},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "{}", resp.Choices[0].Message.Content)
+}